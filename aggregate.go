@@ -0,0 +1,240 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Aggregate is an error that contains multiple errors.
+type Aggregate interface {
+	error
+	Errors() []error
+
+	// GroupByCode returns the errors grouped by their outermost code.
+	// Errors without a code are grouped under the empty string.
+	GroupByCode() map[string][]error
+
+	// Counts returns the number of errors for each outermost code.
+	Counts() map[string]int
+
+	// Occurrences returns how many times the i-th error of Errors occurred.
+	// It is greater than one only in aggregates created by
+	// NewAggregateDeduped.
+	Occurrences(i int) int
+}
+
+// NewAggregate returns an Aggregate of the non-nil errors in errs.
+// If there are none, NewAggregate returns nil.
+func NewAggregate(errs []error) error {
+	var agg aggregate
+	for _, err := range errs {
+		if err != nil {
+			agg = append(agg, err)
+		}
+	}
+
+	if len(agg) == 0 {
+		return nil
+	}
+
+	return agg
+}
+
+type aggregate []error
+
+func (agg aggregate) Errors() []error { return []error(agg) }
+
+func (agg aggregate) GroupByCode() map[string][]error {
+	groups := map[string][]error{}
+	for _, err := range agg {
+		code := FirstCode(err)
+		groups[code] = append(groups[code], err)
+	}
+
+	return groups
+}
+
+func (agg aggregate) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, err := range agg {
+		counts[FirstCode(err)]++
+	}
+
+	return counts
+}
+
+func (agg aggregate) Occurrences(i int) int { return 1 }
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains.
+func (agg aggregate) Unwrap() []error { return []error(agg) }
+
+func (agg aggregate) Error() string {
+	if len(agg) == 1 {
+		return agg[0].Error()
+	}
+
+	msgs := make([]string, len(agg))
+	for i, err := range agg {
+		msgs[i] = err.Error()
+	}
+
+	return "[" + strings.Join(msgs, ", ") + "]"
+}
+
+func (agg aggregate) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range agg {
+				if i > 0 {
+					io.WriteString(s, "\n")
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, agg.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", agg.Error())
+	}
+}
+
+// NewAggregateDeduped returns an Aggregate of the non-nil errors in errs in
+// which identical errors are kept only once, with the number of times they
+// occurred. Errors are identical if they have the same fingerprint: the same
+// outermost code, message and params for coded errors, and the same text for
+// other errors. If there are no errors, NewAggregateDeduped returns nil.
+func NewAggregateDeduped(errs []error) error {
+	d := &dedupedAggregate{}
+	index := map[string]int{}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		fp := fingerprint(err)
+		if i, ok := index[fp]; ok {
+			d.counts[i]++
+			continue
+		}
+
+		index[fp] = len(d.aggregate)
+		d.aggregate = append(d.aggregate, err)
+		d.counts = append(d.counts, 1)
+	}
+
+	if len(d.aggregate) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+// fingerprint identifies err by its outermost code, message and params.
+func fingerprint(err error) string {
+	wc := codeOf(err)
+	if wc == nil {
+		return "\x00" + err.Error()
+	}
+
+	params, _ := json.Marshal(wc.params)
+	return wc.code + "\x00" + wc.Message() + "\x00" + string(params)
+}
+
+type dedupedAggregate struct {
+	aggregate
+	counts []int
+}
+
+func (d *dedupedAggregate) Occurrences(i int) int { return d.counts[i] }
+
+func (d *dedupedAggregate) Counts() map[string]int {
+	counts := map[string]int{}
+	for i, err := range d.aggregate {
+		counts[FirstCode(err)] += d.counts[i]
+	}
+
+	return counts
+}
+
+func (d *dedupedAggregate) Error() string {
+	msgs := make([]string, len(d.aggregate))
+	for i, err := range d.aggregate {
+		msgs[i] = err.Error()
+		if d.counts[i] > 1 {
+			msgs[i] += " (x" + strconv.Itoa(d.counts[i]) + ")"
+		}
+	}
+
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+
+	return "[" + strings.Join(msgs, ", ") + "]"
+}
+
+func (d *dedupedAggregate) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range d.aggregate {
+				if i > 0 {
+					io.WriteString(s, "\n")
+				}
+				if d.counts[i] > 1 {
+					fmt.Fprintf(s, "(x%d) ", d.counts[i])
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, d.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", d.Error())
+	}
+}
+
+// Flatten expands err into its leaf errors: aggregates, and other errors
+// implementing Unwrap() []error such as those of the standard errors.Join,
+// are replaced by their members recursively, preserving order. Errors that
+// wrap an aggregate are expanded into the members of that aggregate.
+// If err is nil, Flatten returns nil.
+func Flatten(err error) []error {
+	return flatten(nil, err, 0)
+}
+
+func flatten(leaves []error, err error, depth int) []error {
+	if err == nil {
+		return leaves
+	}
+
+	var members []error
+	walk(err, func(err error) bool {
+		if multi, ok := err.(multiWrapper); ok {
+			members = multi.Unwrap()
+			if members == nil {
+				members = []error{}
+			}
+			return false
+		}
+
+		return true
+	})
+
+	if members == nil || depth >= MaxChainDepth {
+		return append(leaves, err)
+	}
+
+	for _, member := range members {
+		leaves = flatten(leaves, member, depth+1)
+	}
+
+	return leaves
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewAggregate(t *testing.T) {
+	if got := NewAggregate(nil); got != nil {
+		t.Errorf("NewAggregate(nil): got %v, want nil", got)
+	}
+	if got := NewAggregate([]error{nil, nil}); got != nil {
+		t.Errorf("NewAggregate(nils): got %v, want nil", got)
+	}
+
+	err := NewAggregate([]error{New("first"), nil, NewCode("test.agg", "second")})
+	if got, want := err.Error(), "[first, test.agg - second]"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%q", err), `"[first, test.agg - second]"`; got != want {
+		t.Errorf("%%q: got %s, want %s", got, want)
+	}
+	if got := len(err.(Aggregate).Errors()); got != 2 {
+		t.Errorf("Errors(): got %d errors, want 2", got)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	c.Add(nil)
+	c.Warn(New("slow"))
+	if err := c.Err(); err != nil {
+		t.Errorf("Err() with warnings only: got %v, want nil", err)
+	}
+	if got := len(c.Warnings()); got != 1 {
+		t.Errorf("Warnings(): got %d, want 1", got)
+	}
+
+	first := NewCode("test.collect", "first")
+	c.Add(first)
+	if err := c.Err(); err != first {
+		t.Errorf("Err() with one failure: got %v, want %v", err, first)
+	}
+
+	c.Add(New("second"))
+	if got, want := c.Err().Error(), "[test.collect - first, second]"; got != want {
+		t.Errorf("Err(): got %q, want %q", got, want)
+	}
+}
+
+func TestAggregateGroupByCode(t *testing.T) {
+	timeout := Wrap(NewCode("test.timeout"), "call")
+	agg := NewAggregate([]error{
+		NewCode("test.invalid", "name"),
+		timeout,
+		New("plain"),
+		NewCode("test.invalid", "email"),
+	}).(Aggregate)
+
+	groups := agg.GroupByCode()
+	if got := len(groups["test.invalid"]); got != 2 {
+		t.Errorf("GroupByCode()[test.invalid]: got %d errors, want 2", got)
+	}
+	if got := groups["test.timeout"]; len(got) != 1 || got[0] != timeout {
+		t.Errorf("GroupByCode()[test.timeout]: got %v, want [%v]", got, timeout)
+	}
+
+	want := map[string]int{"test.invalid": 2, "test.timeout": 1, "": 1}
+	if got := agg.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts(): got %v, want %v", got, want)
+	}
+}
+
+// joined mimics the errors returned by the standard errors.Join.
+type joined []error
+
+func (j joined) Error() string   { return "joined" }
+func (j joined) Unwrap() []error { return j }
+
+func TestFlatten(t *testing.T) {
+	a, b, c, d := NewCode("test.a"), NewCode("test.b"), New("c"), NewCode("test.d")
+
+	tests := []struct {
+		err  error
+		want []error
+	}{
+		{nil, nil},
+		{a, []error{a}},
+		{NewAggregate([]error{a, b}), []error{a, b}},
+		{NewAggregate([]error{a, joined{b, NewAggregate([]error{c})}, d}), []error{a, b, c, d}},
+		{Wrap(NewAggregate([]error{a, b}), "batch"), []error{a, b}},
+		{joined{}, nil},
+	}
+
+	for i, tt := range tests {
+		if got := Flatten(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Flatten(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewAggregateDeduped(t *testing.T) {
+	if got := NewAggregateDeduped([]error{nil}); got != nil {
+		t.Errorf("NewAggregateDeduped(nil): got %v, want nil", got)
+	}
+
+	var errs []error
+	for i := 0; i < 10000; i++ {
+		errs = append(errs, NewCodeWithParams("test.dedupe", map[string]interface{}{"shard": 1}, "failed"))
+	}
+	errs = append(errs,
+		NewCodeWithParams("test.dedupe", map[string]interface{}{"shard": 2}, "failed"),
+		New("plain"),
+		New("plain"),
+	)
+
+	agg := NewAggregateDeduped(errs).(Aggregate)
+	if got := len(agg.Errors()); got != 3 {
+		t.Fatalf("Errors(): got %d errors, want 3", got)
+	}
+	for i, want := range []int{10000, 1, 2} {
+		if got := agg.Occurrences(i); got != want {
+			t.Errorf("Occurrences(%d): got %d, want %d", i, got, want)
+		}
+	}
+	if got, want := agg.Counts(), map[string]int{"test.dedupe": 10001, "": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Counts(): got %v, want %v", got, want)
+	}
+	if got, want := agg.Error(), "[test.dedupe - failed (x10000), test.dedupe - failed, plain (x2)]"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", agg), agg.Error(); got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+}
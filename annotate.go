@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AnnotateFrame annotates err with note, associated with the stack frame of
+// the function calling AnnotateFrame. The error text is unchanged; when
+// formatted with %+v, the note is printed below that frame in the stack
+// traces of err, or after them if the frame is not part of any trace:
+//
+//     github.com/acme/app.(*Store).Load
+//             /src/app/store.go:42
+//             note: cache was cold
+//
+// If err is nil, AnnotateFrame returns nil.
+func AnnotateFrame(err error, note string) error {
+	if err == nil {
+		return nil
+	}
+
+	var pcs [1]uintptr
+	providerCallers(1, pcs[:])
+
+	return &withFrameNote{
+		error: err,
+		frame: Frame(pcs[0]),
+		note:  note,
+	}
+}
+
+type withFrameNote struct {
+	error
+	frame Frame
+	note  string
+}
+
+func (w *withFrameNote) Cause() error { return w.error }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFrameNote) Unwrap() error { return w.error }
+
+func (w *withFrameNote) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, insertNote(fmt.Sprintf("%+v", w.error), w.frame, w.note))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// insertNote inserts note below the first frame of trace belonging to the
+// function of frame, or appends it with the location of frame if there is
+// none.
+func insertNote(trace string, frame Frame, note string) string {
+	name := frame.name()
+	if i := strings.Index(trace, "\n"+name+"\n\t"); i >= 0 {
+		i += len(name) + 3
+		if end := strings.IndexByte(trace[i:], '\n'); end >= 0 {
+			i += end
+		} else {
+			i = len(trace)
+		}
+
+		return trace[:i] + "\n\tnote: " + note + trace[i:]
+	}
+
+	return fmt.Sprintf("%s\n%+v\n\tnote: %s", trace, frame, note)
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func annotatedLoad() error {
+	return NewCode("test.annotate", "load failed")
+}
+
+func TestAnnotateFrame(t *testing.T) {
+	err := AnnotateFrame(annotatedLoad(), "cache was cold")
+
+	if got, want := err.Error(), "test.annotate - load failed"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Code(err); got != "test.annotate" {
+		t.Errorf("Code(): got %q, want %q", got, "test.annotate")
+	}
+
+	want := "test.annotate - load failed\n" +
+		"github.com/pkg/errors.annotatedLoad\n" +
+		"\t.+/github.com/pkg/errors/annotate_test.go:11\n" +
+		"github.com/pkg/errors.TestAnnotateFrame\n" +
+		"\t.+/github.com/pkg/errors/annotate_test.go:15\n" +
+		"\tnote: cache was cold\n"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile("^" + want).MatchString(got) {
+		t.Errorf("%%+v: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAnnotateFrameNotInTrace(t *testing.T) {
+	err := AnnotateFrame(io.EOF, "reading header")
+
+	want := "EOF\n" +
+		"github.com/pkg/errors.TestAnnotateFrameNotInTrace\n" +
+		"\t.+/github.com/pkg/errors/annotate_test.go:36\n" +
+		"\tnote: reading header$"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile("^" + want).MatchString(got) {
+		t.Errorf("%%+v: got:\n%s\nwant:\n%s", got, want)
+	}
+	if AnnotateFrame(nil, "note") != nil {
+		t.Errorf("AnnotateFrame(nil): got non-nil")
+	}
+}
@@ -0,0 +1,49 @@
+package errors
+
+import "fmt"
+
+// Assert returns an error with the supplied code and message if cond is
+// false, and nil otherwise. It is meant for invariant checks:
+//
+//     if err := errors.Assert(len(items) > 0, "order.empty"); err != nil {
+//             return err
+//     }
+//
+// Assert also records the stack trace at the point it was called.
+func Assert(cond bool, code string, msgs ...string) error {
+	if cond {
+		return nil
+	}
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// Assertf returns an error with the supplied code and the message formatted
+// according to a format specifier if cond is false, and nil otherwise.
+// The format is only evaluated if cond is false.
+// Assertf also records the stack trace at the point it was called.
+func Assertf(cond bool, code string, format string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+
+	msgs := []string{fmt.Sprintf(format, args...)}
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
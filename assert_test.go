@@ -0,0 +1,28 @@
+package errors
+
+import "testing"
+
+func TestAssert(t *testing.T) {
+	if err := Assert(true, "test.assert"); err != nil {
+		t.Errorf("Assert(true): got %v, want nil", err)
+	}
+	if err := Assertf(true, "test.assert", "count %d", 1); err != nil {
+		t.Errorf("Assertf(true): got %v, want nil", err)
+	}
+
+	err := Assert(false, "test.assert", "empty order")
+	if got := err.Error(); got != "test.assert - empty order" {
+		t.Errorf("Assert(false): got %q", got)
+	}
+	if got := err.(*Error).StackTrace()[0].name(); got != "github.com/pkg/errors.TestAssert" {
+		t.Errorf("Assert(false): stack starts at %s", got)
+	}
+
+	err = Assertf(false, "test.assert", "count %d", 0)
+	if got := err.Error(); got != "test.assert - count 0" {
+		t.Errorf("Assertf(false): got %q", got)
+	}
+	if got := Code(err); got != "test.assert" {
+		t.Errorf("Code(Assertf(false)): got %q, want %q", got, "test.assert")
+	}
+}
@@ -0,0 +1,102 @@
+package errors
+
+import "fmt"
+
+// Base is a coded error meant to be embedded by domain specific error types,
+// so that they carry a code, message, params, cause and stack trace:
+//
+//	type PaymentError struct {
+//	        errors.Base
+//	        Amount int
+//	}
+//
+//	return &PaymentError{Base: errors.NewBase("payment.declined"), Amount: 42}
+//
+// Errors embedding Base are recognized by IsCode, HasCode and the other
+// helpers of this package, and are formatted like errors created with
+// NewCode. A Base must be created with NewBase, NewBaseWithParams or
+// WrapBase; the zero value is not usable.
+type Base struct {
+	err *Error
+}
+
+// NewBase returns a Base with the supplied code and message.
+// NewBase also records the stack trace at the point it was called.
+func NewBase(code string, msgs ...string) Base {
+	return Base{err: &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}}
+}
+
+// NewBaseWithParams returns a Base with the supplied code, params and message.
+// NewBaseWithParams also records the stack trace at the point it was called.
+func NewBaseWithParams(code string, params map[string]interface{}, msgs ...string) Base {
+	return Base{err: &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  params,
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}}
+}
+
+// WrapBase returns a Base annotating err with a stack trace at the point
+// WrapBase is called, and the supplied code and message.
+// Unlike WrapCode, a nil err results in a Base without a cause.
+func WrapBase(err error, code string, msgs ...string) Base {
+	err = checkCode(code, err)
+
+	return Base{err: &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}}
+}
+
+func (b Base) base() *Error { return b.err }
+
+func (b Base) Error() string                  { return b.err.Error() }
+func (b Base) Code() string                   { return b.err.Code() }
+func (b Base) Coder() Coder                   { return b.err.Coder() }
+func (b Base) Message() string                { return b.err.Message() }
+func (b Base) Params() map[string]interface{} { return b.err.Params() }
+func (b Base) FullMessage() string            { return b.err.FullMessage() }
+func (b Base) Action() string                 { return b.err.Action() }
+func (b Base) ErrorID() string                { return b.err.ErrorID() }
+func (b Base) Cause() error                   { return b.err.Cause() }
+func (b Base) StackTrace() StackTrace         { return b.err.StackTrace() }
+func (b Base) Format(s fmt.State, verb rune)  { b.err.Format(s, verb) }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (b Base) Unwrap() error { return b.err.Unwrap() }
+
+// baser is implemented by error types embedding Base.
+type baser interface {
+	base() *Error
+}
+
+// asCode returns err as a *Error, looking through an embedded Base.
+func asCode(err error) (*Error, bool) {
+	switch e := err.(type) {
+	case *Error:
+		return e, true
+	case baser:
+		wc := e.base()
+		return wc, wc != nil
+	}
+
+	return nil, false
+}
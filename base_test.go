@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type paymentError struct {
+	Base
+	Amount int
+}
+
+func TestBase(t *testing.T) {
+	var err error = &paymentError{
+		Base:   WrapBase(io.EOF, "test.payment", "payment declined"),
+		Amount: 42,
+	}
+
+	if !IsCode(err, "test.payment") {
+		t.Errorf("IsCode(%v): got false, want true", err)
+	}
+	if got, want := err.Error(), "test.payment - payment declined: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Cause(err); got != io.EOF {
+		t.Errorf("Cause(): got %v, want %v", got, io.EOF)
+	}
+
+	wrapped := WrapCode(err, "test.outer", "outer")
+	if !HasCode(wrapped, "test.payment") {
+		t.Errorf("HasCode(%v, test.payment): got false, want true", wrapped)
+	}
+	if got, want := RootCode(wrapped), "test.payment"; got != want {
+		t.Errorf("RootCode(): got %q, want %q", got, want)
+	}
+	if got, want := wrapped.Error(), "test.outer - outer: test.payment - payment declined: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+
+	var pe *paymentError
+	if !As(wrapped, &pe) || pe.Amount != 42 {
+		t.Errorf("As(%v): got %v, want Amount 42", wrapped, pe)
+	}
+}
+
+func TestNewBaseWithParams(t *testing.T) {
+	err := &paymentError{Base: NewBaseWithParams("test.payment", map[string]interface{}{"id": 7}, "payment {id}")}
+
+	if got := Params(err)["id"]; got != 7 {
+		t.Errorf("Params(): got %v, want 7", got)
+	}
+	if got, want := fmt.Sprintf("%v", err), "test.payment - payment {id}"; got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", err), "test.payment - payment {id}\ngithub.com/pkg/errors.TestNewBaseWithParams"; len(got) < len(want) || got[:len(want)] != want {
+		t.Errorf("%%+v: got %q, want prefix %q", got, want)
+	}
+}
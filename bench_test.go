@@ -108,3 +108,56 @@ func BenchmarkStackFormatting(b *testing.B) {
 	}
 	GlobalE = stackStr
 }
+
+func BenchmarkNewCode(b *testing.B) {
+	Register(testCoder{code: "bench.code", message: "registered"})
+
+	runs := []struct {
+		name string
+		msgs []string
+	}{
+		{"registry-message", nil},
+		{"explicit-message", []string{"explicit"}},
+	}
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = NewCode("bench.code", r.msgs...)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkCodeMatching(b *testing.B) {
+	coded := NewCode("bench.match.root")
+	for i := 0; i < 4; i++ {
+		coded = WrapCode(coded, "bench.match.layer")
+	}
+	wrapped := Wrap(coded, "annotated")
+
+	runs := []struct {
+		name  string
+		match func(error, string) bool
+		err   error
+	}{
+		{"IsCode", IsCode, coded},
+		{"HasCode-coded-chain", HasCode, coded},
+		{"HasCode-annotated-chain", HasCode, wrapped},
+		{"HasCode-uncoded", HasCode, stderrors.New("plain")},
+		{"HasAnyCode-coded-chain", func(err error, code string) bool { return HasAnyCode(err, code, "bench.other") }, coded},
+	}
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if r.match(r.err, "bench.missing") {
+					b.Fatal("unexpected match")
+				}
+			}
+		})
+	}
+}
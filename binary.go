@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"encoding/gob"
+	"encoding/json"
+)
+
+func init() {
+	// Register *Error so coded errors can be gob encoded as values of
+	// interface type error, e.g. in job queue payloads.
+	gob.Register(&Error{})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The chain of w is
+// encoded in the wire format of ToJSON; stack traces are not encoded.
+func (w *Error) MarshalBinary() ([]byte, error) {
+	return json.Marshal(ToWire(w))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for data produced
+// by MarshalBinary. data is decoded as FromJSON does, so payloads larger
+// than MaxWireBytes are rejected and integer params keep their type. The
+// stack trace of the decoded error is recorded at the point UnmarshalBinary
+// is called.
+func (w *Error) UnmarshalBinary(data []byte) error {
+	err, perr := FromJSON(data)
+	if perr != nil {
+		return perr
+	}
+
+	wc, ok := err.(*Error)
+	if !ok {
+		return New("errors: binary error is not a coded error")
+	}
+	*w = *wc
+
+	return nil
+}
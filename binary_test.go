@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	want := WrapCode(Wrap(io.EOF, "read"), "test.binary", "failed")
+
+	data, err := want.(*Error).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &Error{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error() != want.Error() || got.Code() != "test.binary" {
+		t.Errorf("round trip: got %q, want %q", got, want)
+	}
+
+	if err := got.UnmarshalBinary([]byte(`{"chain":[{"message":"plain"}]}`)); err == nil {
+		t.Errorf("UnmarshalBinary of uncoded error: want error")
+	}
+	if err := got.UnmarshalBinary([]byte(`{`)); err == nil {
+		t.Errorf("UnmarshalBinary of invalid data: want error")
+	}
+	if err := got.UnmarshalBinary(make([]byte, MaxWireBytes+1)); err == nil {
+		t.Errorf("UnmarshalBinary of oversized data: want error")
+	}
+
+	data, err = NewCodeWithParams("test.binary", map[string]interface{}{"n": int64(1 << 60)}, "failed").(*Error).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := got.Params()["n"].(int64); !ok || n != 1<<60 {
+		t.Errorf("UnmarshalBinary: got param %#v, want int64(%d)", got.Params()["n"], int64(1<<60))
+	}
+}
+
+func TestGob(t *testing.T) {
+	type job struct {
+		Name string
+		Err  error
+	}
+
+	want := job{Name: "resize", Err: NewCodeWithParams("test.gob", map[string]interface{}{"id": "7"}, "failed")}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got job
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !EquivalentForTest(got.Err, want.Err) {
+		t.Errorf("gob: got %v, want %v", got.Err, want.Err)
+	}
+}
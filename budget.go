@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+type budgetKey struct{}
+
+// errorBudget is the budget added to a context by WithErrorBudget.
+type errorBudget struct {
+	mu        sync.Mutex
+	remaining int
+	errs      []error
+}
+
+// WithErrorBudget returns a copy of ctx carrying a budget of n non-fatal
+// errors, so that a best-effort fan-out can tolerate some failing calls
+// before failing the request as a whole:
+//
+//     ctx = errors.WithErrorBudget(ctx, 2)
+//     for _, shard := range shards {
+//             if err := shard.Query(ctx, q); !errors.ConsumeBudget(ctx, err) {
+//                     return err
+//             }
+//     }
+//
+// Contexts derived from the returned context share its budget.
+func WithErrorBudget(ctx context.Context, n int) context.Context {
+	if n < 0 {
+		n = 0
+	}
+
+	return context.WithValue(ctx, budgetKey{}, &errorBudget{remaining: n})
+}
+
+// ConsumeBudget charges err to the budget of ctx and reports whether it may
+// be tolerated. A nil err is always tolerated and consumes nothing. Coded
+// errors are non-fatal: each one consumes one unit of the budget and is
+// tolerated while the budget lasts. Errors without a code, and all errors
+// if ctx carries no budget, are fatal and are never tolerated.
+// Tolerated errors are returned by BudgetErrors.
+// ConsumeBudget is safe for concurrent use.
+func ConsumeBudget(ctx context.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	b, _ := ctx.Value(budgetKey{}).(*errorBudget)
+	if b == nil || codeOf(err) == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining == 0 {
+		return false
+	}
+	b.remaining--
+	b.errs = append(b.errs, err)
+
+	return true
+}
+
+// BudgetErrors returns the errors tolerated by ConsumeBudget with the budget
+// of ctx, so that they can be logged or reported with the response: nil if
+// there are none, the error itself if there is exactly one, and an
+// Aggregate of the errors otherwise.
+func BudgetErrors(ctx context.Context) error {
+	b, _ := ctx.Value(budgetKey{}).(*errorBudget)
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch len(b.errs) {
+	case 0:
+		return nil
+	case 1:
+		return b.errs[0]
+	}
+
+	return NewAggregate(append([]error(nil), b.errs...))
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestErrorBudget(t *testing.T) {
+	ctx := WithErrorBudget(context.Background(), 2)
+
+	if !ConsumeBudget(ctx, nil) {
+		t.Error("ConsumeBudget(nil): got false, want true")
+	}
+	if ConsumeBudget(ctx, io.EOF) {
+		t.Error("ConsumeBudget(uncoded): got true, want false")
+	}
+
+	first, second := NewCode("test.budget"), NewCode("test.budget")
+	if !ConsumeBudget(ctx, first) || !ConsumeBudget(ctx, second) {
+		t.Error("ConsumeBudget: got false within the budget, want true")
+	}
+	if ConsumeBudget(ctx, NewCode("test.budget")) {
+		t.Error("ConsumeBudget: got true with an exhausted budget, want false")
+	}
+
+	agg, ok := BudgetErrors(ctx).(Aggregate)
+	if !ok {
+		t.Fatalf("BudgetErrors: got %T, want Aggregate", BudgetErrors(ctx))
+	}
+	if errs := agg.Errors(); len(errs) != 2 || errs[0] != first || errs[1] != second {
+		t.Errorf("BudgetErrors: got %v, want [%v %v]", errs, first, second)
+	}
+
+	if ConsumeBudget(context.Background(), NewCode("test.budget")) {
+		t.Error("ConsumeBudget without a budget: got true, want false")
+	}
+	if err := BudgetErrors(context.Background()); err != nil {
+		t.Errorf("BudgetErrors without a budget: got %v, want nil", err)
+	}
+}
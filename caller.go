@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// packageDir is the source directory of this package, used to recognize
+// frames inside the package.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// Caller returns the function, file and line where the root-most error of
+// err's chain that records a stack trace was created, skipping frames inside
+// this package and the runtime. Empty stack traces, such as those of the
+// sentinels of NewSentinel, are skipped. It is meant for log fields such as
+// origin=pkg/store.Save:42 that do not need the whole stack.
+// The boolean reports whether a frame was found.
+func Caller(err error) (function, file string, line int, ok bool) {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	var st StackTrace
+	walk(err, func(err error) bool {
+		if tracer, ok := err.(stackTracer); ok {
+			if trace := tracer.StackTrace(); len(trace) > 0 {
+				st = trace
+			}
+		}
+
+		return true
+	})
+
+	for _, f := range st {
+		info := f.resolve()
+		if info.name == "unknown" || internalFrame(info.name, info.file) {
+			continue
+		}
+
+		return info.name, info.file, info.line, true
+	}
+
+	return "", "", 0, false
+}
+
+// internalFrame reports whether the frame of function in file belongs to
+// this package or to the runtime.
+func internalFrame(function, file string) bool {
+	if strings.HasPrefix(function, "runtime.") {
+		return true
+	}
+
+	return filepath.Dir(file) == packageDir && !strings.HasSuffix(file, "_test.go")
+}
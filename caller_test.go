@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaller(t *testing.T) {
+	save := func() (err error) {
+		defer DeferWrap(&err, "test.caller")
+		return New("disk full") // origin
+	}
+
+	function, file, line, ok := Caller(Wrap(save(), "save"))
+	if !ok {
+		t.Fatalf("Caller: no frame found")
+	}
+	if !strings.HasSuffix(function, "TestCaller.func1") {
+		t.Errorf("Caller: function %q, want TestCaller.func1", function)
+	}
+	if filepath.Base(file) != "caller_test.go" || line != 13 {
+		t.Errorf("Caller: got %s:%d, want caller_test.go:13", file, line)
+	}
+
+	// Errors without stack trace, such as sentinels and the causes made
+	// by FromPanic, leave the frame to the errors wrapping them.
+	for _, err := range []error{
+		WrapCode(NewSentinel("test.caller.sentinel"), "test.caller"),
+		FromPanic("boom", "test.caller"),
+		Truncate(WrapCode(WrapCode(io.EOF, "test.caller"), "test.caller"), 1),
+	} {
+		if _, file, _, ok := Caller(err); !ok || filepath.Base(file) != "caller_test.go" {
+			t.Errorf("Caller(%v): got %s, %v, want caller_test.go", err, file, ok)
+		}
+	}
+
+	if _, _, _, ok := Caller(nil); ok {
+		t.Errorf("Caller(nil): got ok")
+	}
+	if _, _, _, ok := Caller(errorString("no stack")); ok {
+		t.Errorf("Caller without stack: got ok")
+	}
+}
+
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
@@ -0,0 +1,84 @@
+package errors
+
+import "sync"
+
+// OtherLabel is the label CodeLabeler gives the unregistered codes beyond
+// its limit.
+const OtherLabel = "other"
+
+// CodeLabeler maps codes to the values of metric and log labels, bounding
+// the number of distinct values: registered codes are their own label, as
+// the catalog bounds them, and so are the first max distinct unregistered
+// codes seen; later unregistered codes are collapsed into OtherLabel. This
+// protects metric backends such as Prometheus and log indexes from codes
+// built from request data:
+//
+//     var labeler = errors.NewCodeLabeler(100)
+//
+//     errorsTotal.WithLabelValues(labeler.Label(err)).Inc()
+//
+// A CodeLabeler is safe for concurrent use.
+type CodeLabeler struct {
+	max int
+
+	mu   sync.RWMutex
+	seen map[string]bool
+}
+
+// NewCodeLabeler returns a CodeLabeler keeping at most max distinct
+// unregistered codes as labels. With a max of zero, all unregistered codes
+// are labeled OtherLabel.
+func NewCodeLabeler(max int) *CodeLabeler {
+	return &CodeLabeler{
+		max:  max,
+		seen: map[string]bool{},
+	}
+}
+
+// Label returns the label of the outermost code in err's chain, see
+// LabelCode. Errors without a code are labeled with the empty string.
+func (l *CodeLabeler) Label(err error) string {
+	return l.LabelCode(FirstCode(err))
+}
+
+// LabelCode returns the label of code: code itself if it is registered or
+// one of the unregistered codes kept, and OtherLabel otherwise. The empty
+// code is its own label.
+func (l *CodeLabeler) LabelCode(code string) string {
+	if code == "" || GetCoder(code) != nil {
+		return code
+	}
+
+	l.mu.RLock()
+	kept, full := l.seen[code], len(l.seen) >= l.max
+	l.mu.RUnlock()
+
+	switch {
+	case kept:
+		return code
+	case full:
+		return OtherLabel
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.seen[code] {
+		if len(l.seen) >= l.max {
+			return OtherLabel
+		}
+		l.seen[code] = true
+	}
+
+	return code
+}
+
+// Collapsed reports whether unregistered codes are being collapsed into
+// OtherLabel because max distinct unregistered codes have been seen, so
+// that the condition can be alerted on.
+func (l *CodeLabeler) Collapsed() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.seen) >= l.max
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCodeLabeler(t *testing.T) {
+	Register(testCoder{code: "test.labels.registered"})
+	l := NewCodeLabeler(2)
+
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"test.labels.registered", "test.labels.registered"},
+		{"test.labels.a", "test.labels.a"},
+		{"test.labels.b", "test.labels.b"},
+		{"test.labels.c", OtherLabel},
+		{"test.labels.a", "test.labels.a"},
+		{"test.labels.registered", "test.labels.registered"},
+		{"", ""},
+	}
+
+	for i, tt := range tests {
+		if got := l.LabelCode(tt.code); got != tt.want {
+			t.Errorf("test %d: LabelCode(%q): got %q, want %q", i+1, tt.code, got, tt.want)
+		}
+	}
+
+	if got := l.Label(Wrap(NewCode("test.labels.d"), "call")); got != OtherLabel {
+		t.Errorf("Label: got %q, want %q", got, OtherLabel)
+	}
+	if got := l.Label(New("plain")); got != "" {
+		t.Errorf("Label(plain): got %q, want empty", got)
+	}
+	if !l.Collapsed() {
+		t.Errorf("Collapsed: got false, want true")
+	}
+
+	if got := NewCodeLabeler(0).LabelCode("test.labels.a"); got != OtherLabel {
+		t.Errorf("max 0: got %q, want %q", got, OtherLabel)
+	}
+}
+
+func TestCodeLabelerConcurrent(t *testing.T) {
+	l := NewCodeLabeler(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.LabelCode(fmt.Sprintf("test.labels.concurrent.%d", i*100+j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	labels := map[string]bool{}
+	for i := 0; i < 800; i++ {
+		labels[l.LabelCode(fmt.Sprintf("test.labels.concurrent.%d", i))] = true
+	}
+	if len(labels) != 11 {
+		t.Errorf("got %d distinct labels, want 11", len(labels))
+	}
+}
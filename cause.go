@@ -0,0 +1,70 @@
+package errors
+
+// ReplaceCause returns a copy of err in which the cause of the outermost
+// coded error is replaced by cause. The code, message, params, ID and stack
+// trace of the coded error are kept, as are the layers added above it by
+// Wrap, WithMessage, WithStack and WithRetryAfter. The text of the old
+// cause is removed from messages that include it, such as those of NewCodef.
+// This is useful to sanitize errors before they cross a trust boundary,
+// for example by replacing a database error with a generic one.
+// If err has no coded error that can be reached through the layers of this
+// package, err is returned unchanged.
+func ReplaceCause(err error, cause error) error {
+	update := func(wc *Error) {
+		wc.unwrapMessage()
+		wc.cause = cause
+	}
+	if replaced, ok := updateCoded(err, update, 0); ok {
+		return replaced
+	}
+
+	return err
+}
+
+// StripCause returns a copy of err in which the outermost coded error has
+// no cause, as ReplaceCause(err, nil) does.
+func StripCause(err error) error {
+	return ReplaceCause(err, nil)
+}
+
+// updateCoded returns a copy of err in which update has been applied to a
+// clone of the outermost coded error, keeping the layers of this package
+// above it. It reports false if no coded error is reached through them.
+func updateCoded(err error, update func(wc *Error), depth int) (error, bool) {
+	if depth >= MaxChainDepth {
+		return nil, false
+	}
+
+	switch e := err.(type) {
+	case *Error:
+		wc := e.clone()
+		update(&wc)
+		return &wc, true
+	case *withMessage:
+		if inner, ok := updateCoded(e.cause, update, depth+1); ok {
+			return &withMessage{cause: inner, msg: e.msg}, true
+		}
+	case *withStack:
+		if inner, ok := updateCoded(e.error, update, depth+1); ok {
+			return &withStack{inner, e.stack}, true
+		}
+	case *withRetryAfter:
+		if inner, ok := updateCoded(e.error, update, depth+1); ok {
+			return &withRetryAfter{inner, e.after}, true
+		}
+	case *withUpstream:
+		if inner, ok := updateCoded(e.error, update, depth+1); ok {
+			return &withUpstream{inner, e.upstream}, true
+		}
+	case *withoutCause:
+		if inner, ok := updateCoded(e.error, update, depth+1); ok {
+			return &withoutCause{inner}, true
+		}
+	case *withFrameNote:
+		if inner, ok := updateCoded(e.error, update, depth+1); ok {
+			return &withFrameNote{inner, e.frame, e.note}, true
+		}
+	}
+
+	return nil, false
+}
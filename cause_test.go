@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestReplaceCause(t *testing.T) {
+	coded := WrapCode(io.EOF, "test.replace", "read failed")
+	sanitized := New("internal error")
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{coded, "test.replace - read failed: internal error"},
+		{Wrap(coded, "context"), "context: test.replace - read failed: internal error"},
+		{WithRetryAfter(coded, time.Second), "test.replace - read failed: internal error"},
+	}
+
+	for i, tt := range tests {
+		got := ReplaceCause(tt.err, sanitized)
+		if got.Error() != tt.want {
+			t.Errorf("test %d: ReplaceCause: got %q, want %q", i+1, got.Error(), tt.want)
+		}
+		if got := FirstCode(got); got != "test.replace" {
+			t.Errorf("test %d: FirstCode: got %q, want %q", i+1, got, "test.replace")
+		}
+		if RootCause(got) != sanitized {
+			t.Errorf("test %d: RootCause: got %v, want %v", i+1, RootCause(got), sanitized)
+		}
+	}
+
+	if got, want := coded.Error(), "test.replace - read failed: EOF"; got != want {
+		t.Errorf("original modified: got %q, want %q", got, want)
+	}
+	if got := ReplaceCause(io.EOF, sanitized); got != io.EOF {
+		t.Errorf("ReplaceCause(uncoded): got %v, want %v", got, io.EOF)
+	}
+}
+
+func TestStripCause(t *testing.T) {
+	coded := WrapCode(io.EOF, "test.strip", "read failed")
+
+	got := StripCause(Wrap(coded, "context"))
+	if want := "context: test.strip - read failed"; got.Error() != want {
+		t.Errorf("StripCause: got %q, want %q", got.Error(), want)
+	}
+	if codeOf(got).stack != coded.(*Error).stack {
+		t.Errorf("StripCause: stack trace not kept")
+	}
+}
+
+func TestStripCauseNewCodef(t *testing.T) {
+	err := NewCodef("test.strip.codef", "query failed: %w", New("password=hunter2"))
+
+	got := StripCause(err)
+	if want := "test.strip.codef - query failed"; got.Error() != want {
+		t.Errorf("StripCause: got %q, want %q", got.Error(), want)
+	}
+	if w := ToWire(got); len(w.Chain) != 1 || w.Chain[0].Wrapped {
+		t.Errorf("ToWire: got %+v, want a single unwrapped layer", w.Chain)
+	}
+
+	// The message of decoded errors is stripped as well.
+	decoded, perr := FromJSON(mustJSON(t, err))
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	if got := StripCause(decoded).Error(); got != "test.strip.codef - query failed" {
+		t.Errorf("StripCause(decoded): got %q", got)
+	}
+}
+
+func TestReplaceCauseNewCodef(t *testing.T) {
+	err := NewCodef("test.replace.codef", "read %s: %w", "config", New("password=hunter2"))
+
+	got := ReplaceCause(err, io.EOF)
+	if want := "test.replace.codef - read config: EOF"; got.Error() != want {
+		t.Errorf("ReplaceCause: got %q, want %q", got.Error(), want)
+	}
+	if Cause(got) != io.EOF {
+		t.Errorf("ReplaceCause: got cause %v, want io.EOF", Cause(got))
+	}
+}
+
+func mustJSON(t *testing.T, err error) []byte {
+	data, jerr := ToJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+
+	return data
+}
@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MaxChainDepth limits the number of errors visited when this package walks
+// an error chain, for example in Error, HasCode or when formatting with %+v.
+// Chains that are deeper, or that contain a cycle, are truncated and the
+// truncation is marked with "...".
+var MaxChainDepth = 256
+
+// next returns the error wrapped by err, or nil if err does not wrap one.
+func next(err error) error {
+	type causer interface {
+		Cause() error
+	}
+
+	type wrapper interface {
+		Unwrap() error
+	}
+
+	switch e := err.(type) {
+	case causer:
+		return e.Cause()
+	case wrapper:
+		return e.Unwrap()
+	}
+
+	return nil
+}
+
+// walk calls fn for err and each error in its chain, outermost first,
+// until fn returns false or the chain ends.
+// It reports whether the chain was truncated, either because it is deeper
+// than MaxChainDepth or because it contains a cycle.
+func walk(err error, fn func(error) bool) bool {
+	// Cycles are detected with Brent's algorithm: mark is moved forward to
+	// the current error whenever steps reaches a power of two.
+	var mark error
+	steps, power := 0, 1
+
+	for depth := 0; err != nil; depth++ {
+		if depth >= MaxChainDepth {
+			return true
+		}
+
+		if mark != nil && reflect.TypeOf(err).Comparable() && err == mark {
+			return true
+		}
+
+		if !fn(err) {
+			return false
+		}
+
+		if steps++; steps == power {
+			mark, steps, power = err, 0, power*2
+		}
+
+		err = next(err)
+	}
+
+	return false
+}
+
+// multiWrapper is implemented by errors wrapping several errors, such as
+// aggregates and the errors of the standard errors.Join.
+type multiWrapper interface {
+	Unwrap() []error
+}
+
+// walkTree calls fn for err and each error in its chain as walk does, and
+// walks the members of errors implementing Unwrap() []error in order,
+// instead of calling fn for them. fn returning false ends the walk of the
+// branch it was called for; the other branches are still walked.
+func walkTree(err error, fn func(error) bool) {
+	walkTreeDepth(err, fn, 0)
+}
+
+func walkTreeDepth(err error, fn func(error) bool, depth int) {
+	walk(err, func(err error) bool {
+		if multi, ok := err.(multiWrapper); ok {
+			if depth < MaxChainDepth {
+				for _, member := range multi.Unwrap() {
+					walkTreeDepth(member, fn, depth+1)
+				}
+			}
+			return false
+		}
+
+		return fn(err)
+	})
+}
+
+// RootCause returns the deepest error in err's chain. Unlike Cause, it
+// unwraps through errors implementing either Cause() error or Unwrap() error,
+// so chains mixing this package with fmt.Errorf("%w") are followed to the end.
+// If err is nil, nil is returned.
+func RootCause(err error) error {
+	walk(err, func(e error) bool {
+		err = e
+		return true
+	})
+
+	return err
+}
+
+// chainError returns the Error text of err, rendering the errors of this
+// package iteratively so that deep or cyclic chains are truncated.
+func chainError(err error) string {
+	return renderChain(err, false)
+}
+
+// renderChain renders the Error text of err as chainError does. If
+// stopAtCode is true, the text ends with the outermost coded error.
+func renderChain(err error, stopAtCode bool) string {
+	text, _ := renderChainCacheable(err, stopAtCode)
+	return text
+}
+
+// renderChainCacheable renders the Error text of err as renderChain does,
+// and reports whether the text may be cached: it may not if a coded error
+// of the chain reads its message from the registry on every call.
+func renderChainCacheable(err error, stopAtCode bool) (string, bool) {
+	var b strings.Builder
+	cacheable := true
+
+	truncated := walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok {
+			err = wc
+		}
+
+		switch e := err.(type) {
+		case *Error:
+			cacheable = cacheable && e.source != messageLazy
+			b.WriteString(e.code + " - " + e.Message())
+			if stopAtCode || e.wrapped {
+				return false
+			}
+			if e.cause != nil {
+				b.WriteString(": ")
+			}
+		case *withMessage:
+			b.WriteString(e.msg + ": ")
+		case *withStack, *withFrameNote:
+		default:
+			b.WriteString(err.Error())
+			return false
+		}
+
+		return true
+	})
+	if truncated {
+		b.WriteString("...")
+	}
+
+	return b.String(), cacheable
+}
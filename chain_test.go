@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// loop is a buggy wrapper whose chain may point back to itself.
+type loop struct{ next error }
+
+func (l *loop) Error() string { return "loop" }
+func (l *loop) Unwrap() error { return l.next }
+
+func TestWalkCycle(t *testing.T) {
+	l := &loop{}
+	l.next = WrapCode(WithMessage(l, "again"), "test.cycle")
+
+	visited := 0
+	truncated := walk(l, func(error) bool {
+		visited++
+		return true
+	})
+	if !truncated {
+		t.Errorf("walk: want cycle to truncate the chain")
+	}
+	if visited >= MaxChainDepth {
+		t.Errorf("walk: visited %d errors, want cycle detected before MaxChainDepth", visited)
+	}
+}
+
+func TestChainDepthLimit(t *testing.T) {
+	err := New("root")
+	for i := 0; i < MaxChainDepth*2; i++ {
+		err = WrapCode(err, "test.deep", "layer")
+	}
+
+	if got := err.Error(); !strings.HasSuffix(got, "...") {
+		t.Errorf("Error(): got %q, want truncation marker", got[len(got)-20:])
+	}
+	if HasCode(err, "test.missing") {
+		t.Errorf("HasCode: got true, want false")
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "...\n") {
+		t.Errorf("%%+v: got %q, want truncation marker", got[:20])
+	}
+}
+
+func TestChainError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewCode("test.code", "msg"), "test.code - msg"},
+		{WrapCode(New("root"), "test.code", "msg"), "test.code - msg: root"},
+		{WithMessage(WrapCode(New("root"), "test.code", "msg"), "ctx"), "ctx: test.code - msg: root"},
+		{WrapCode(Wrap(New("root"), "wrapped"), "test.code", "msg"), "test.code - msg: wrapped: root"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+// unwrapper wraps an error using only the Go 1.13 Unwrap method.
+type unwrapper struct{ err error }
+
+func (u *unwrapper) Error() string { return "unwrapper: " + u.err.Error() }
+func (u *unwrapper) Unwrap() error { return u.err }
+
+func TestRootCause(t *testing.T) {
+	root := New("root")
+
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},
+		{root, root},
+		{Wrap(root, "wrapped"), root},
+		{WrapCode(&unwrapper{Wrap(root, "wrapped")}, "test.code"), root},
+	}
+
+	for i, tt := range tests {
+		if got := RootCause(tt.err); got != tt.want {
+			t.Errorf("test %d: RootCause: got %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestRootCode(t *testing.T) {
+	err := WrapCode(&unwrapper{WrapCode(New("root"), "test.root")}, "test.outer")
+
+	if got, want := RootCode(err), "test.root"; got != want {
+		t.Errorf("RootCode: got %q, want %q", got, want)
+	}
+	if got := RootCode(New("plain")); got != "" {
+		t.Errorf("RootCode: got %q, want empty", got)
+	}
+}
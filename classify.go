@@ -0,0 +1,70 @@
+// +build go1.13
+
+package errors
+
+import (
+	stderrors "errors"
+	"reflect"
+	"sync"
+)
+
+type stdMapping struct {
+	target error
+	code   string
+}
+
+// stdMappings contains the sentinel errors registered with MapStdError.
+var stdMappings []stdMapping
+var stdMappingMux = &sync.RWMutex{}
+
+// MapStdError registers code as the code of errors matching target, such as
+// sql.ErrNoRows, io.EOF or os.ErrNotExist, for use by Classify.
+// Mappings are tried in registration order; mapping the same target again
+// replaces its code, if the target is comparable.
+func MapStdError(target error, code string) {
+	stdMappingMux.Lock()
+	defer stdMappingMux.Unlock()
+
+	// Targets of non comparable types, which would panic when compared, are
+	// never replaced.
+	comparable := target == nil || reflect.TypeOf(target).Comparable()
+	for i := range stdMappings {
+		if comparable && stdMappings[i].target == target {
+			stdMappings[i].code = code
+			return
+		}
+	}
+
+	stdMappings = append(stdMappings, stdMapping{target: target, code: code})
+}
+
+// Classify annotates err with the code mapped by MapStdError to the first
+// registered sentinel error that err matches, as reported by Is.
+// Errors that already carry a code, or that match no mapping, are returned
+// unchanged. If err is nil, Classify returns nil.
+func Classify(err error) error {
+	if err == nil || codeOf(err) != nil {
+		return err
+	}
+
+	stdMappingMux.RLock()
+	defer stdMappingMux.RUnlock()
+
+	for _, m := range stdMappings {
+		if stderrors.Is(err, m.target) {
+			err = checkCode(m.code, err)
+
+			return &Error{
+				code:    m.code,
+				message: explicitMessage(nil),
+				source:  messageSourceOf(nil),
+				cause:   err,
+				id:      errorID(err),
+				seq:     recordWrap(m.code),
+				stack:   callers(),
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,51 @@
+// +build go1.13
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	Register(testCoder{code: "test.classify.eof", message: "unexpected end of input"})
+	MapStdError(io.EOF, "test.classify.eof")
+	MapStdError(os.ErrNotExist, "test.classify.missing")
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{io.EOF, "test.classify.eof - unexpected end of input: EOF"},
+		{fmt.Errorf("read: %w", io.EOF), "test.classify.eof - unexpected end of input: read: EOF"},
+		{Wrap(os.ErrNotExist, "open"), "test.classify.missing - : open: file does not exist"},
+		{NewCode("test.code", "coded"), "test.code - coded"},
+		{New("plain"), "plain"},
+	}
+
+	for i, tt := range tests {
+		if got := Classify(tt.err).Error(); got != tt.want {
+			t.Errorf("test %d: Classify(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if Classify(nil) != nil {
+		t.Errorf("Classify(nil): want nil")
+	}
+}
+
+type sliceError []string
+
+func (e sliceError) Error() string { return "slice error" }
+
+func TestMapStdErrorNotComparable(t *testing.T) {
+	MapStdError(sliceError{"a"}, "test.classify.slice")
+	MapStdError(sliceError{"b"}, "test.classify.slice")
+	MapStdError(io.ErrUnexpectedEOF, "test.classify.unexpected")
+
+	if got := Classify(io.ErrUnexpectedEOF); codeOf(got) == nil || codeOf(got).Code() != "test.classify.unexpected" {
+		t.Errorf("Classify(io.ErrUnexpectedEOF): got %v", got)
+	}
+}
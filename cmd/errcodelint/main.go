@@ -0,0 +1,16 @@
+// Command errcodelint checks the use of coded errors.
+//
+// Usage:
+//
+//     errcodelint [-registry codes.txt] [packages]
+//
+// See package github.com/pkg/errors/errcodelint for the checks performed.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/pkg/errors/errcodelint"
+)
+
+func main() { multichecker.Main(errcodelint.Analyzer, errcodelint.CompareAnalyzer) }
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // Coder defines an interface for an error code detail information.
@@ -28,21 +31,30 @@ type Coder interface {
 	Reference() string
 }
 
+// Actioner is implemented by Coders that suggest what the user should do
+// about the error, such as "retry later" or "contact support with ID
+// {request_id}". Placeholders in braces are replaced with the error params.
+type Actioner interface {
+	Action() string
+}
+
 // codes contains a map of error codes to metadata.
 var codes = map[string]Coder{}
-var codeMux = &sync.Mutex{}
+var codeMux = &sync.RWMutex{}
 
 // Register register a user define error code.
 // It will overrid the exist code.
+// It panics if the registry is frozen by FreezeRegistry.
 func Register(coder Coder) {
 	codeMux.Lock()
 	defer codeMux.Unlock()
 
-	codes[coder.Code()] = coder
+	register(coder)
 }
 
 // MustRegister register a user define error code.
-// It will panic when the same Code already exist.
+// It will panic when the same Code already exist, or when the Code is in a
+// range reserved by ReserveRange for another owner than the Owner of coder.
 func MustRegister(coder Coder) {
 	codeMux.Lock()
 	defer codeMux.Unlock()
@@ -50,12 +62,93 @@ func MustRegister(coder Coder) {
 	if _, ok := codes[coder.Code()]; ok {
 		panic(fmt.Sprintf("code: %s already exist", coder.Code()))
 	}
+	if err := checkReservation(coder); err != nil {
+		panic(err.Error())
+	}
+	if n, ok := coder.(NumCoder); ok {
+		if code, ok := numCodes[n.NumCode()]; ok {
+			panic(fmt.Sprintf("code: numeric code %d of %s already used by %s", n.NumCode(), coder.Code(), code))
+		}
+	}
 
-	codes[coder.Code()] = coder
+	register(coder)
+}
+
+// RegisterAll registers coders as one batch: either all of them are
+// registered, or, if any of them fails the checks of MustRegister, none is.
+// The Coders must also have a non-empty code, and their codes and numeric
+// codes must be distinct within the batch. It is meant for the init
+// functions of packages registering their codes:
+//
+//     func init() {
+//             if err := errors.RegisterAll(userCodes...); err != nil {
+//                     panic(err)
+//             }
+//     }
+//
+// The returned error is an Aggregate with one error per failed check.
+func RegisterAll(coders ...Coder) error {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	if frozenRegistry() != nil {
+		return New("errors: register codes: registry is frozen")
+	}
+
+	var failures []error
+	batch := map[string]bool{}
+	batchNums := map[int]string{}
+	for i, coder := range coders {
+		if coder == nil {
+			failures = append(failures, Errorf("code: coder %d is nil", i))
+			continue
+		}
+
+		code := coder.Code()
+		switch {
+		case code == "":
+			failures = append(failures, Errorf("code: coder %d has an empty code", i))
+			continue
+		case batch[code]:
+			failures = append(failures, Errorf("code: %s is registered twice", code))
+			continue
+		case codes[code] != nil:
+			failures = append(failures, Errorf("code: %s already exist", code))
+		}
+		batch[code] = true
+
+		if err := checkReservation(coder); err != nil {
+			failures = append(failures, err)
+		}
+		if n, ok := coder.(NumCoder); ok {
+			if other, ok := numCodes[n.NumCode()]; ok {
+				failures = append(failures, Errorf("code: numeric code %d of %s already used by %s", n.NumCode(), code, other))
+			} else if other, ok := batchNums[n.NumCode()]; ok {
+				failures = append(failures, Errorf("code: numeric code %d of %s already used by %s", n.NumCode(), code, other))
+			}
+			batchNums[n.NumCode()] = code
+		}
+	}
+	if len(failures) > 0 {
+		return NewAggregate(failures)
+	}
+
+	for _, coder := range coders {
+		register(coder)
+	}
+
+	return nil
 }
 
 // GetCoder return the coder by code.
 func GetCoder(code string) Coder {
+	if f := frozenRegistry(); f != nil {
+		return f.lookup(code)
+	}
+
+	codeMux.RLock()
+	defer codeMux.RUnlock()
+
 	if coder, ok := codes[code]; ok {
 		return coder
 	}
@@ -63,17 +156,43 @@ func GetCoder(code string) Coder {
 	return nil
 }
 
-// ParseCoder parse any error into *withCode.
-// nil error will return nil direct.
-// None withCode error will be parsed as nil.
+// CodesWithStatus returns the sorted registered codes whose Coder has the
+// HTTP status code status, for example to map the status of a response
+// back to a code.
+func CodesWithStatus(status int) []string {
+	codeMux.RLock()
+	var found []string
+	for code, coder := range codes {
+		if coder.StatusCode() == status {
+			found = append(found, code)
+		}
+	}
+	codeMux.RUnlock()
+
+	sort.Strings(found)
+	return found
+}
+
+// ParseCoder returns the Coder of err if err is a coded error, as returned
+// by its Coder method. Only err itself is inspected, not its causes.
+// ParseCoder returns nil if err is nil, is not a coded error or has no
+// Coder, such as an error with an unregistered code. For errors wrapping
+// several errors, such as those of the standard errors.Join, the Coder of
+// the first member that has one is returned.
 func ParseCoder(err error) Coder {
 	if err == nil {
 		return nil
 	}
 
-	if wc, ok := err.(*withCode); ok {
-		if coder, ok := codes[wc.code]; ok {
-			return coder
+	if wc, ok := asCode(err); ok {
+		return wc.Coder()
+	}
+
+	if multi, ok := err.(multiWrapper); ok {
+		for _, member := range multi.Unwrap() {
+			if coder := ParseCoder(member); coder != nil {
+				return coder
+			}
 		}
 	}
 
@@ -82,7 +201,7 @@ func ParseCoder(err error) Coder {
 
 // IsCode reports whether the error's code is the given code.
 func IsCode(err error, code string) bool {
-	if coder, ok := err.(*withCode); ok {
+	if coder, ok := asCode(err); ok {
 		if coder.code == code {
 			return true
 		}
@@ -92,43 +211,299 @@ func IsCode(err error, code string) bool {
 }
 
 // HasCode reports whether any error in err's chain contains the given error code.
+// The members of errors wrapping several errors, such as those of the
+// standard errors.Join, are searched as well.
 func HasCode(err error, code string) bool {
-	if coder, ok := err.(*withCode); ok {
-		if coder.code == code {
+	// Fast path for chains of coded errors, which need neither the
+	// interface conversions nor the cycle detection of walk.
+	for depth := 0; ; depth++ {
+		wc, ok := err.(*Error)
+		if !ok {
+			break
+		}
+		if wc.code == code {
 			return true
 		}
+		if wc.cause == nil || depth+1 >= MaxChainDepth {
+			return false
+		}
+		err = wc.cause
+	}
 
-		if coder.cause != nil {
-			return HasCode(coder.cause, code)
+	found := false
+	walkTree(err, func(err error) bool {
+		wc, ok := asCode(err)
+		if !ok || found {
+			return false
 		}
 
-		return false
+		found = wc.code == code
+		return !found
+	})
+
+	return found
+}
+
+// HasAnyCode reports whether any error in err's chain contains one of the
+// given error codes, including the members of errors wrapping several
+// errors as for HasCode. The chain is walked only once.
+func HasAnyCode(err error, codes ...string) bool {
+	// Fast path for chains of coded errors, as in HasCode.
+	for depth := 0; ; depth++ {
+		wc, ok := err.(*Error)
+		if !ok {
+			break
+		}
+		for _, code := range codes {
+			if wc.code == code {
+				return true
+			}
+		}
+		if wc.cause == nil || depth+1 >= MaxChainDepth {
+			return false
+		}
+		err = wc.cause
 	}
 
-	return false
+	found := false
+	walkTree(err, func(err error) bool {
+		wc, ok := asCode(err)
+		if !ok || found {
+			return false
+		}
+
+		for _, code := range codes {
+			if wc.code == code {
+				found = true
+				break
+			}
+		}
+
+		return !found
+	})
+
+	return found
 }
 
-type withCode struct {
-	code    string
-	message string
-	params  map[string]interface{}
-	cause   error
+// HasCodePrefix reports whether any error in err's chain contains an error
+// code starting with prefix, for example "auth." for a code namespace.
+// The members of errors wrapping several errors are searched as for HasCode.
+func HasCodePrefix(err error, prefix string) bool {
+	// Fast path for chains of coded errors, as in HasCode.
+	for depth := 0; ; depth++ {
+		wc, ok := err.(*Error)
+		if !ok {
+			break
+		}
+		if strings.HasPrefix(wc.code, prefix) {
+			return true
+		}
+		if wc.cause == nil || depth+1 >= MaxChainDepth {
+			return false
+		}
+		err = wc.cause
+	}
+
+	found := false
+	walkTree(err, func(err error) bool {
+		wc, ok := asCode(err)
+		if !ok || found {
+			return false
+		}
+
+		found = strings.HasPrefix(wc.code, prefix)
+		return !found
+	})
+
+	return found
+}
+
+// Match reports whether any coded error in err's chain satisfies pred.
+// pred is called with the code of each coded error, outermost first, and
+// its Coder, which is nil when the code is not registered. The members of
+// errors wrapping several errors, such as those of the standard errors.Join,
+// are searched as well.
+func Match(err error, pred func(code string, c Coder) bool) bool {
+	matched := false
+	walkTree(err, func(err error) bool {
+		if matched {
+			return false
+		}
+		if wc, ok := asCode(err); ok {
+			matched = pred(wc.code, wc.Coder())
+		}
+
+		return !matched
+	})
+
+	return matched
+}
+
+// Codes returns the codes of all coded errors in err's chain, outermost
+// first, without duplicates. The members of errors wrapping several errors,
+// such as those of the standard errors.Join, are included in order.
+// If there is no coded error in the chain, Codes returns nil.
+func Codes(err error) []string {
+	var found []string
+	seen := map[string]bool{}
+	walkTree(err, func(err error) bool {
+		if wc, ok := asCode(err); ok && !seen[wc.code] {
+			seen[wc.code] = true
+			found = append(found, wc.code)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// FirstCode returns the outermost code in err's chain.
+// If there is no coded error in the chain, the empty string is returned.
+func FirstCode(err error) string {
+	if wc := codeOf(err); wc != nil {
+		return wc.code
+	}
+
+	return ""
+}
+
+// CodeAt returns the code of the coded error at depth in err's chain,
+// counting only coded errors. Depth 0 is the outermost code; negative depths
+// count from the root, so -1 is the root-most code. The coded errors of the
+// members of errors wrapping several errors, such as those of the standard
+// errors.Join, are counted in order, as Codes lists them.
+// If there is no coded error at depth, the empty string is returned.
+func CodeAt(err error, depth int) string {
+	var found []string
+	walkTree(err, func(err error) bool {
+		if depth >= 0 && len(found) > depth {
+			return false
+		}
+		if wc, ok := asCode(err); ok {
+			found = append(found, wc.code)
+		}
+
+		return depth < 0 || len(found) <= depth
+	})
+
+	if depth < 0 {
+		depth += len(found)
+	}
+	if depth < 0 || depth >= len(found) {
+		return ""
+	}
+
+	return found[depth]
+}
+
+// RootCode returns the root-most code in err's chain.
+// If there is no coded error in the chain, the empty string is returned.
+func RootCode(err error) string {
+	return CodeAt(err, -1)
+}
+
+// Error is a coded error, as created by NewCode, WrapCode and the other
+// constructors of this package, which return it as an error. Its fields are
+// read through getters, so that it can be inspected after a type switch or
+// As without being modified:
+//
+//     var e *errors.Error
+//     if errors.As(err, &e) {
+//             log.Println(e.Code(), e.Params(), e.Cause(), e.StackTrace())
+//     }
+type Error struct {
+	code     string
+	message  string
+	params   map[string]interface{}
+	cause    error
+	coder    Coder
+	id       string
+	source   messageSource
+	resolved atomic.Value // string, set by Message for messageDeferred
+	text     atomic.Value // string, the Error text cached by Error
+	spawn    *stack
+	panicked *stack
+	wrapped  bool             // the message includes the text of the cause, see NewCodef
+	bare     string           // the message without the text of the cause, if wrapped
+	restored []persistedFrame // the stack trace before SaveError, see LoadError
+	seq      uint64           // the construction recorded by recordWrap, or 0
 	*stack
 }
 
 type fullMessage struct {
 	Params  map[string]interface{} `json:"params"`
 	Message string                 `json:"message"`
+	Action  string                 `json:"action,omitempty"`
 }
 
-func (w *withCode) Code() string { return w.code }
+func (w *Error) Code() string { return w.code }
 
-func (w *withCode) Message() string { return w.message }
+// Coder returns the Coder of w, or nil if its code is not registered.
+func (w *Error) Coder() Coder {
+	if lazy, ok := w.coder.(*lazyCoder); ok {
+		if coder := lazy.resolve(); coder != nil {
+			return coder
+		}
+	}
+	if w.coder != nil {
+		return w.coder
+	}
 
-func (w *withCode) Params() map[string]interface{} { return w.params }
+	return GetCoder(w.code)
+}
+
+func (w *Error) Message() string {
+	switch w.source {
+	case messageLazy:
+		return registryMessage(w.code)
+	case messageDeferred:
+		if msg, ok := w.resolved.Load().(string); ok {
+			return msg
+		}
 
-func (w *withCode) FullMessage() string {
-	fullMsg := fullMessage{Message: w.message, Params: w.params}
+		msg := registryMessage(w.code)
+		w.resolved.Store(msg)
+		return msg
+	}
+
+	return w.message
+}
+
+// clone returns a copy of w without its cached Error text, as the copy is
+// usually given another cause. The message and text of w are resolved
+// first, so that the copy does not race with concurrent calls of Message
+// and Error.
+func (w *Error) clone() Error {
+	w.Message()
+	_ = w.Error()
+
+	c := *w
+	c.text = atomic.Value{}
+	return c
+}
+
+// unwrapMessage removes the text of the cause from the message of w, if it
+// includes it, so that w can be given another cause. The message of errors
+// decoded by FromWire, for which it is not known, is stripped of the text
+// of the cause.
+func (w *Error) unwrapMessage() {
+	if !w.wrapped {
+		return
+	}
+
+	if w.bare != "" || w.cause == nil {
+		w.message = w.bare
+	} else {
+		w.message = trimSeparators(strings.Replace(w.message, w.cause.Error(), "", 1))
+	}
+	w.wrapped, w.bare = false, ""
+}
+
+func (w *Error) Params() map[string]interface{} { return w.params }
+
+func (w *Error) FullMessage() string {
+	fullMsg := fullMessage{Message: w.Message(), Params: w.params, Action: w.Action()}
 	if fullMsg.Params == nil {
 		fullMsg.Params = map[string]interface{}{}
 	}
@@ -141,30 +516,101 @@ func (w *withCode) FullMessage() string {
 	return string(message)
 }
 
-func (w *withCode) Cause() error { return w.cause }
+// Action returns the suggested user action of w's Coder with the params of w
+// filled in, or the empty string if the Coder does not implement Actioner.
+func (w *Error) Action() string {
+	if actioner, ok := w.Coder().(Actioner); ok {
+		return expand(actioner.Action(), w.params)
+	}
+
+	return ""
+}
+
+// ErrorID returns the instance ID of w, or the empty string if IDs are not
+// generated.
+func (w *Error) ErrorID() string { return w.id }
+
+func (w *Error) Cause() error { return w.cause }
+
+// SpawnStackTrace returns the stack of the goroutine spawn site recorded by
+// WrapCodeCtx or GoWrap, or nil if there is none.
+func (w *Error) SpawnStackTrace() StackTrace {
+	if w.spawn == nil {
+		return nil
+	}
+
+	return w.spawn.StackTrace()
+}
+
+func (w *Error) Error() string {
+	if text, ok := w.text.Load().(string); ok {
+		return text
+	}
 
-func (w *withCode) Error() string {
-	errString := w.code + " - " + w.message
-	if w.cause != nil {
-		errString += ": " + w.cause.Error()
+	text, cacheable := renderChainCacheable(w, false)
+	if cacheable && CacheErrorText {
+		w.text.Store(text)
 	}
 
-	return errString
+	return text
 }
 
+// CacheErrorText makes coded errors keep their Error text once it has been
+// rendered, since logging frameworks may call Error several times for one
+// event. The text is not kept for chains with errors created while
+// LazyMessages is set, whose messages follow the registry; errors of other
+// packages in the chain are assumed not to change their text.
+// It should be set during program initialization.
+var CacheErrorText = true
+
 // Unwrap provides compatibility for Go 1.13 error chains.
-func (w *withCode) Unwrap() error { return w.cause }
+func (w *Error) Unwrap() error { return w.cause }
 
-func (w *withCode) Format(s fmt.State, verb rune) {
+func (w *Error) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
-			if w.Cause() != nil {
-				fmt.Fprintf(s, "%+v\n", w.Cause())
+			var layers []*Error
+			var cause error
+			truncated := walk(w, func(err error) bool {
+				if wc, ok := asCode(err); ok {
+					layers = append(layers, wc)
+					return true
+				}
+
+				cause = err
+				return false
+			})
+
+			if truncated {
+				io.WriteString(s, "...\n")
+			} else if cause != nil {
+				formatCause(s, cause)
 			}
 
-			io.WriteString(s, w.code+" - "+w.message)
-			w.stack.Format(s, verb)
+			for i := len(layers) - 1; i >= 0; i-- {
+				io.WriteString(s, layers[i].code+" - "+layers[i].Message())
+				if i == 0 && w.id != "" {
+					io.WriteString(s, " ["+w.id+"]")
+				}
+				if layers[i].panicked != nil {
+					io.WriteString(s, "\npanicked at:")
+					layers[i].panicked.Format(s, verb)
+					io.WriteString(s, "\nrecovered at:")
+				}
+				if layers[i].restored != nil {
+					formatRestored(s, layers[i].restored)
+				} else {
+					layers[i].stack.Format(s, verb)
+				}
+				if layers[i].spawn != nil {
+					io.WriteString(s, "\nspawned by:")
+					layers[i].spawn.Format(s, verb)
+				}
+				if i > 0 {
+					io.WriteString(s, "\n")
+				}
+			}
 			return
 		}
 		fallthrough
@@ -173,136 +619,257 @@ func (w *withCode) Format(s fmt.State, verb rune) {
 	}
 }
 
+// formatCause writes the root cause of a chain formatted with %+v. The
+// members of errors wrapping several errors that do not format themselves,
+// such as those of the standard errors.Join, are written one after another,
+// so that their codes and stack traces are not hidden.
+func formatCause(s io.Writer, cause error) {
+	formatCauseDepth(s, cause, 0)
+}
+
+func formatCauseDepth(s io.Writer, cause error, depth int) {
+	if multi, ok := cause.(multiWrapper); ok && depth < MaxChainDepth {
+		if _, ok := cause.(fmt.Formatter); !ok {
+			for _, member := range multi.Unwrap() {
+				if member != nil {
+					formatCauseDepth(s, member, depth+1)
+				}
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(s, "%+v\n", cause)
+}
+
 // Code returns the underlying code of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// The code is taken from the first error in err's chain, outermost first,
+// that implements the following interface:
 //
 //     type coder interface {
-//            Code() error
+//            Code() string
 //     }
 //
-// If the error does not implement Code or the error is nil,
+// so a coded error wrapped by WithMessage or fmt.Errorf("%w") still yields
+// its code. If no error in the chain implements Code or the error is nil,
 // the empty string will be returned.
 func Code(err error) string {
-	code := ""
-	if err == nil {
-		return code
-	}
-
 	type coder interface {
 		Code() string
 	}
 
-	cd, ok := err.(coder)
-	if ok {
-		code = cd.Code()
-	}
+	code := ""
+	walk(err, func(err error) bool {
+		cd, ok := err.(coder)
+		if ok {
+			code = cd.Code()
+		}
+
+		return !ok
+	})
 
 	return code
 }
 
 // Message returns the underlying message of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// The message is taken from the first error in err's chain that implements
+// the following interface:
 //
 //     type messager interface {
-//            Message() error
+//            Message() string
 //     }
 //
-// If the error does not implement Message or the error is nil,
+// If no error in the chain implements Message or the error is nil,
 // the empty string will be returned.
 func Message(err error) string {
-	msg := ""
-	if err == nil {
-		return msg
-	}
-
 	type messager interface {
 		Message() string
 	}
 
-	msger, ok := err.(messager)
-	if ok {
-		msg = msger.Message()
-	}
+	msg := ""
+	walk(err, func(err error) bool {
+		msger, ok := err.(messager)
+		if ok {
+			msg = msger.Message()
+		}
+
+		return !ok
+	})
 
 	return msg
 }
 
 // FullMessage returns the underlying full message of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// The full message is taken from the first error in err's chain that
+// implements the following interface:
 //
 //     type fullmessager interface {
-//            FullMessage() error
+//            FullMessage() string
 //     }
 //
-// If the error does not implement FullMessage or the error is nil,
+// If no error in the chain implements FullMessage or the error is nil,
 // the empty string will be returned.
 func FullMessage(err error) string {
-	fullMsg := ""
-	if err == nil {
-		return fullMsg
-	}
-
 	type fullmessager interface {
 		FullMessage() string
 	}
 
-	fullMsger, ok := err.(fullmessager)
-	if ok {
-		fullMsg = fullMsger.FullMessage()
-	}
+	fullMsg := ""
+	walk(err, func(err error) bool {
+		fullMsger, ok := err.(fullmessager)
+		if ok {
+			fullMsg = fullMsger.FullMessage()
+		}
+
+		return !ok
+	})
 
 	return fullMsg
 }
 
+// Action returns the underlying suggested user action of the error, if possible.
+// The action is taken from the first error in err's chain that implements
+// the following interface:
+//
+//     type actioner interface {
+//            Action() string
+//     }
+//
+// If no error in the chain implements Action or the error is nil,
+// the empty string will be returned.
+func Action(err error) string {
+	action := ""
+	walk(err, func(err error) bool {
+		actioner, ok := err.(Actioner)
+		if ok {
+			action = actioner.Action()
+		}
+
+		return !ok
+	})
+
+	return action
+}
+
 // Params returns the underlying params of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// The params are taken from the first error in err's chain that implements
+// the following interface:
 //
 //     type parameter interface {
-//            Params() error
+//            Params() map[string]interface{}
 //     }
 //
-// If the error does not implement parameter or the error is nil,
-// the nil will be returned.
+// If no error in the chain implements Params or the error is nil,
+// nil will be returned.
 func Params(err error) map[string]interface{} {
-	if err == nil {
-		return nil
-	}
-
 	type parameter interface {
 		Params() map[string]interface{}
 	}
 
-	paramer, ok := err.(parameter)
-	if ok {
-		return paramer.Params()
-	}
+	var params map[string]interface{}
+	walk(err, func(err error) bool {
+		paramer, ok := err.(parameter)
+		if ok {
+			params = paramer.Params()
+		}
 
-	return nil
+		return !ok
+	})
+
+	return params
 }
 
 // NewCode returns an error with the supplied code and message.
 // NewCode also records the stack trace at the point it was called.
 func NewCode(code string, msgs ...string) error {
-	return &withCode{
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// NewCodef returns an error with the supplied code and a message formatted
+// according to a format specifier. As with fmt.Errorf, the errors of %w
+// verbs are wrapped: the error of a single %w verb is the cause of the
+// returned error, and the errors of several %w verbs are its causes, in the
+// order of the verbs:
+//
+//     err := errors.NewCodef("config.load", "read %s: %w", path, err)
+//
+// The text of the causes is part of the message, so it is not repeated by
+// Error. NewCodef also records the stack trace at the point it was called.
+func NewCodef(code, format string, args ...interface{}) error {
+	msg, wrapped := errorf(format, args)
+
+	var cause error
+	switch len(wrapped) {
+	case 0:
+	case 1:
+		cause = wrapped[0]
+	default:
+		cause = aggregate(wrapped)
+	}
+	cause = checkCode(code, cause)
+
+	return &Error{
 		code:    code,
-		message: message(code, msgs),
+		message: msg,
+		cause:   cause,
+		id:      errorID(cause),
+		wrapped: len(wrapped) > 0,
+		bare:    bareMessage(format, args, wrapped),
+		seq:     recordWrap(code),
 		stack:   callers(),
 	}
 }
 
 func NewCodeWithParams(code string, params map[string]interface{}, msgs ...string) error {
-	return &withCode{
+	return &Error{
 		code:    code,
-		message: message(code, msgs),
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
 		params:  params,
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
 		stack:   callers(),
 	}
 }
 
+// FromCoder returns an error with the code and message of coder.
+// The params default to the params of coder and can be overridden by
+// passing params. The coder does not need to be registered; ParseCoder
+// returns it for the resulting error.
+// FromCoder also records the stack trace at the point it was called.
+// If coder is nil, FromCoder returns nil.
+func FromCoder(coder Coder, params ...map[string]interface{}) error {
+	if coder == nil {
+		return nil
+	}
+
+	countCode(coder.Code(), nil)
+
+	wc := &Error{
+		code:    coder.Code(),
+		message: coder.Message(),
+		params:  coder.Params(),
+		coder:   coder,
+		id:      errorID(nil),
+		seq:     recordWrap(coder.Code()),
+		stack:   callers(),
+	}
+	if len(params) > 0 {
+		wc.params = params[0]
+	}
+
+	return wc
+}
+
 // WrapCode returns an error annotating err with a stack trace
 // at the point WrapCode is called, and the supplied code and message.
 // If err is nil, WrapCode returns nil.
@@ -311,10 +878,15 @@ func WrapCode(err error, code string, msgs ...string) error {
 		return nil
 	}
 
-	return &withCode{
+	err = checkCode(code, err)
+
+	return &Error{
 		code:    code,
-		message: message(code, msgs),
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
 		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
 		stack:   callers(),
 	}
 }
@@ -324,24 +896,226 @@ func WrapCodeWithParams(err error, code string, params map[string]interface{}, m
 		return nil
 	}
 
-	return &withCode{
+	err = checkCode(code, err)
+
+	return &Error{
 		code:    code,
-		message: message(code, msgs),
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
 		params:  params,
 		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
 		stack:   callers(),
 	}
 }
 
-func message(code string, msgs []string) string {
-	message := ""
+// WrapCodeIf returns an error annotating err with a stack trace and the
+// supplied code and message, as WrapCode does, if cond is true.
+// Otherwise err is returned unchanged.
+// If err is nil, WrapCodeIf returns nil.
+func WrapCodeIf(cond bool, err error, code string, msgs ...string) error {
+	if err == nil || !cond {
+		return err
+	}
+
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// WrapUnlessCode returns an error annotating err with a stack trace and the
+// supplied code and message, as WrapCode does, unless err's chain already
+// contains code, in which case err is returned unchanged. The whole chain is
+// searched, including the errors below layers without a code, such as those
+// added by Wrap, and the members of errors wrapping several errors, such as
+// those of the standard errors.Join.
+// If err is nil, WrapUnlessCode returns nil.
+func WrapUnlessCode(err error, code string, msgs ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	found := false
+	walkTree(err, func(err error) bool {
+		if wc, ok := asCode(err); ok && !found {
+			found = wc.code == code
+		}
+
+		return !found
+	})
+	if found {
+		return err
+	}
+
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// DeferWrap annotates the error pointed to by errp with a stack trace and
+// the supplied code and message, as WrapCode does. It is meant to be deferred
+// by functions with a named error result:
+//
+//     func (s *Store) Save(v Value) (err error) {
+//             defer errors.DeferWrap(&err, "store.save")
+//             ...
+//     }
+//
+// If errp or *errp is nil, DeferWrap does nothing.
+func DeferWrap(errp *error, code string, msgs ...string) {
+	if errp == nil || *errp == nil {
+		return
+	}
+
+	*errp = checkCode(code, *errp)
+
+	*errp = &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		cause:   *errp,
+		id:      errorID(*errp),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// LazyMessages makes coded errors created without an explicit message read
+// the message of their registered Coder each time it is needed, instead of
+// once, the first time it is needed, so that later registry updates such as
+// Reload are reflected. It should be set during program initialization.
+var LazyMessages = false
+
+// messageSource is where the message of a coded error comes from.
+type messageSource uint8
+
+const (
+	// messageExplicit is the message given at construction.
+	messageExplicit messageSource = iota
+
+	// messageDeferred is the registry message, resolved once by Message.
+	messageDeferred
+
+	// messageLazy is the registry message, resolved on every Message call.
+	messageLazy
+)
+
+// messageSourceOf returns the messageSource of a coded error created with
+// msgs. The registry is not consulted at construction, so that creating
+// coded errors does not access the registry.
+func messageSourceOf(msgs []string) messageSource {
+	switch {
+	case len(msgs) > 0:
+		return messageExplicit
+	case LazyMessages:
+		return messageLazy
+	}
+	return messageDeferred
+}
+
+// MessageFallback, if set, returns the message of coded errors created
+// without a message whose code is not registered, which otherwise have an
+// empty message and an Error text of "code - ". CodeAsMessage and
+// UnknownErrorMessage are ready-made fallbacks:
+//
+//     errors.MessageFallback = errors.CodeAsMessage
+//
+// MessageFallback should be set during program initialization.
+var MessageFallback func(code string) string
+
+// CodeAsMessage returns code. It is meant to be used as MessageFallback.
+func CodeAsMessage(code string) string { return code }
+
+// UnknownErrorMessage returns "unknown error". It is meant to be used as
+// MessageFallback.
+func UnknownErrorMessage(code string) string { return "unknown error" }
+
+// explicitMessage returns the message of a coded error created with msgs,
+// or the empty string if its message is resolved from the registry.
+func explicitMessage(msgs []string) string {
 	if len(msgs) == 0 {
-		if coder, ok := codes[code]; ok {
-			message = coder.Message()
+		return ""
+	}
+
+	return msgs[0]
+}
+
+// registryMessage returns the message of the registered Coder of code, or
+// the MessageFallback message if code is not registered.
+func registryMessage(code string) string {
+	if coder := GetCoder(code); coder != nil {
+		return coder.Message()
+	}
+	if MessageFallback != nil {
+		return MessageFallback(code)
+	}
+
+	return ""
+}
+
+// AsError returns the outermost coded error in err's chain, including the
+// coded error of a type embedding Base, and reports whether there is one.
+func AsError(err error) (*Error, bool) {
+	wc := codeOf(err)
+	return wc, wc != nil
+}
+
+// codeOf returns the outermost *Error in err's chain, or nil if there is none.
+func codeOf(err error) *Error {
+	var wc *Error
+	walk(err, func(err error) bool {
+		wc, _ = asCode(err)
+		return wc == nil
+	})
+
+	return wc
+}
+
+// expand replaces the {name} placeholders in tmpl with the matching params.
+// Placeholders without a matching param are kept as is.
+func expand(tmpl string, params map[string]interface{}) string {
+	if len(params) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			break
 		}
-	} else {
-		message = msgs[0]
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(tmpl[:start])
+		if v, ok := params[tmpl[start+1:end]]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(tmpl[start : end+1])
+		}
+		tmpl = tmpl[end+1:]
 	}
+	b.WriteString(tmpl)
 
-	return message
+	return b.String()
 }
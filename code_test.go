@@ -0,0 +1,499 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCoder struct {
+	code    string
+	status  int
+	message string
+	params  map[string]interface{}
+}
+
+func (c testCoder) Code() string                   { return c.code }
+func (c testCoder) StatusCode() int                { return c.status }
+func (c testCoder) Message() string                { return c.message }
+func (c testCoder) Params() map[string]interface{} { return c.params }
+func (c testCoder) FullMessage() string            { return c.message }
+func (c testCoder) Reference() string              { return "" }
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{New("plain"), ""},
+		{NewCode("test.code", "message"), "test.code"},
+		{Wrap(NewCode("test.code", "message"), "wrapped"), "test.code"},
+		{WrapCode(NewCode("test.inner"), "test.outer"), "test.outer"},
+		{WithMessage(NewCode("test.code"), "annotated"), "test.code"},
+	}
+
+	for i, tt := range tests {
+		got := ""
+		if wc := codeOf(tt.err); wc != nil {
+			got = wc.code
+		}
+		if got != tt.want {
+			t.Errorf("test %d: codeOf(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFromCoder(t *testing.T) {
+	coder := testCoder{code: "test.unregistered", status: 400, message: "bad input", params: map[string]interface{}{"field": "name"}}
+
+	err := FromCoder(coder)
+	if got, want := err.Error(), "test.unregistered - bad input"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := Params(err)["field"]; got != "name" {
+		t.Errorf("Params: got %v, want %q", got, "name")
+	}
+	if got := ParseCoder(err); got.Code() != coder.Code() {
+		t.Errorf("ParseCoder: got %v, want %v", got, coder)
+	}
+
+	err = FromCoder(coder, map[string]interface{}{"field": "email"})
+	if got := Params(err)["field"]; got != "email" {
+		t.Errorf("Params: got %v, want %q", got, "email")
+	}
+
+	if FromCoder(nil) != nil {
+		t.Errorf("FromCoder(nil): want nil")
+	}
+}
+
+func TestDeferWrap(t *testing.T) {
+	save := func(fail bool) (err error) {
+		defer DeferWrap(&err, "test.save", "save failed")
+		if fail {
+			return New("disk full")
+		}
+		return nil
+	}
+
+	if err := save(false); err != nil {
+		t.Errorf("DeferWrap: got %v, want nil", err)
+	}
+
+	err := save(true)
+	if got, want := err.Error(), "test.save - save failed: disk full"; got != want {
+		t.Errorf("DeferWrap: got %q, want %q", got, want)
+	}
+
+	DeferWrap(nil, "test.save")
+}
+
+func TestWrapCodeIf(t *testing.T) {
+	err := New("root")
+
+	if got := WrapCodeIf(false, err, "test.cond"); got != err {
+		t.Errorf("WrapCodeIf(false): got %v, want %v", got, err)
+	}
+	if got := WrapCodeIf(true, err, "test.cond", "cond"); got.Error() != "test.cond - cond: root" {
+		t.Errorf("WrapCodeIf(true): got %q", got)
+	}
+	if got := WrapCodeIf(true, nil, "test.cond"); got != nil {
+		t.Errorf("WrapCodeIf(true, nil): got %v, want nil", got)
+	}
+}
+
+func TestWrapUnlessCode(t *testing.T) {
+	err := WrapUnlessCode(New("root"), "test.retry", "retry")
+	if got, want := err.Error(), "test.retry - retry: root"; got != want {
+		t.Errorf("WrapUnlessCode: got %q, want %q", got, want)
+	}
+
+	if got := WrapUnlessCode(err, "test.retry", "retry"); got != err {
+		t.Errorf("WrapUnlessCode: got %q, want unchanged %q", got, err)
+	}
+
+	wrapped := Wrap(NewCode("test.retry", "retry"), "ctx")
+	if got := WrapUnlessCode(wrapped, "test.retry", "retry"); got != wrapped {
+		t.Errorf("WrapUnlessCode: got %q, want unchanged %q", got, wrapped)
+	}
+
+	joinedErr := joined{New("plain"), NewCode("test.retry", "retry")}
+	if got := WrapUnlessCode(joinedErr, "test.retry", "retry"); !reflect.DeepEqual(got, joinedErr) {
+		t.Errorf("WrapUnlessCode: got %q, want unchanged %q", got, joinedErr)
+	}
+
+	if got := WrapUnlessCode(nil, "test.retry"); got != nil {
+		t.Errorf("WrapUnlessCode(nil): got %v, want nil", got)
+	}
+}
+
+func TestFirstCode(t *testing.T) {
+	err := WithMessage(WrapCode(Wrap(NewCode("test.inner"), "io"), "test.outer"), "ctx")
+
+	if got, want := FirstCode(err), "test.outer"; got != want {
+		t.Errorf("FirstCode: got %q, want %q", got, want)
+	}
+	if got := FirstCode(New("plain")); got != "" {
+		t.Errorf("FirstCode: got %q, want empty", got)
+	}
+}
+
+func TestCodeAt(t *testing.T) {
+	err := WrapCode(Wrap(WrapCode(NewCode("test.root"), "test.middle"), "io"), "test.outer")
+
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		{0, "test.outer"},
+		{1, "test.middle"},
+		{2, "test.root"},
+		{3, ""},
+		{-1, "test.root"},
+		{-3, "test.outer"},
+		{-4, ""},
+	}
+
+	for _, tt := range tests {
+		if got := CodeAt(err, tt.depth); got != tt.want {
+			t.Errorf("CodeAt(%d): got %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+	tree := WrapCode(joined{NewCode("test.left"), WrapCode(NewCode("test.leaf"), "test.right")}, "test.outer")
+	for depth, want := range []string{"test.outer", "test.left", "test.right", "test.leaf", ""} {
+		if got := CodeAt(tree, depth); got != want {
+			t.Errorf("CodeAt(joined, %d): got %q, want %q", depth, got, want)
+		}
+	}
+	if got := RootCode(tree); got != "test.leaf" {
+		t.Errorf("RootCode(joined): got %q, want %q", got, "test.leaf")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	Register(testCoder{code: "test.match.notfound", status: 404, message: "not found"})
+
+	err := WrapCode(Wrap(NewCode("test.match.notfound"), "io"), "test.match.unregistered")
+
+	clientError := func(code string, c Coder) bool {
+		return c != nil && c.StatusCode() >= 400 && c.StatusCode() < 500
+	}
+	if !Match(err, clientError) {
+		t.Errorf("Match(4xx): got false, want true")
+	}
+
+	var seen []string
+	Match(err, func(code string, c Coder) bool {
+		seen = append(seen, code)
+		return false
+	})
+	if len(seen) != 2 || seen[0] != "test.match.unregistered" || seen[1] != "test.match.notfound" {
+		t.Errorf("Match: visited %v", seen)
+	}
+
+	if !Match(joined{New("plain"), NewCode("test.match.notfound")}, clientError) {
+		t.Errorf("Match(joined): got false, want true")
+	}
+
+	if Match(New("plain"), clientError) {
+		t.Errorf("Match(plain): got true, want false")
+	}
+}
+
+func TestHasAnyCode(t *testing.T) {
+	err := WrapCode(NewCode("test.root"), "test.outer")
+
+	if !HasAnyCode(err, "test.missing", "test.root") {
+		t.Errorf("HasAnyCode: got false, want true")
+	}
+	if HasAnyCode(err, "test.missing", "test.other") {
+		t.Errorf("HasAnyCode: got true, want false")
+	}
+	if HasAnyCode(err) {
+		t.Errorf("HasAnyCode(no codes): got true, want false")
+	}
+}
+
+func TestHasCodePrefix(t *testing.T) {
+	err := WrapCode(NewCode("auth.token.expired"), "api.request")
+
+	if !HasCodePrefix(err, "auth.") {
+		t.Errorf("HasCodePrefix(auth.): got false, want true")
+	}
+	if HasCodePrefix(err, "store.") {
+		t.Errorf("HasCodePrefix(store.): got true, want false")
+	}
+}
+
+func TestAction(t *testing.T) {
+	Register(actionCoder{testCoder{code: "test.action", message: "failed"}, "contact support with ID {request_id} {missing}"})
+
+	err := NewCodeWithParams("test.action", map[string]interface{}{"request_id": "r-1"})
+	if got, want := Action(err), "contact support with ID r-1 {missing}"; got != want {
+		t.Errorf("Action: got %q, want %q", got, want)
+	}
+	if got, want := FullMessage(err), `{"params":{"request_id":"r-1"},"message":"failed","action":"contact support with ID r-1 {missing}"}`; got != want {
+		t.Errorf("FullMessage: got %s, want %s", got, want)
+	}
+	if got := Action(NewCode("test.code")); got != "" {
+		t.Errorf("Action: got %q, want empty", got)
+	}
+}
+
+func TestAccessorsWalkChain(t *testing.T) {
+	coded := NewCodeWithParams("test.inner", map[string]interface{}{"id": 7}, "inner failed")
+
+	tests := []error{
+		WithMessage(coded, "context"),
+		&unwrapper{coded},
+		Wrap(&unwrapper{WithMessage(coded, "context")}, "outer"),
+	}
+
+	for i, err := range tests {
+		if got, want := Code(err), "test.inner"; got != want {
+			t.Errorf("test %d: Code: got %q, want %q", i+1, got, want)
+		}
+		if got, want := Message(err), "inner failed"; got != want {
+			t.Errorf("test %d: Message: got %q, want %q", i+1, got, want)
+		}
+		if got := Params(err)["id"]; got != 7 {
+			t.Errorf("test %d: Params: got %v, want 7", i+1, got)
+		}
+	}
+
+	if got := Code(New("plain")); got != "" {
+		t.Errorf("Code(plain): got %q, want empty", got)
+	}
+	if got := Code(nil); got != "" {
+		t.Errorf("Code(nil): got %q, want empty", got)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	err := Wrap(WrapCodeWithParams(io.EOF, "test.aserror", map[string]interface{}{"id": 7}), "load")
+
+	e, ok := AsError(err)
+	if !ok {
+		t.Fatalf("AsError(%v): got false, want true", err)
+	}
+	if e.Code() != "test.aserror" || e.Params()["id"] != 7 || e.Cause() != io.EOF {
+		t.Errorf("AsError: got code %q, params %v, cause %v", e.Code(), e.Params(), e.Cause())
+	}
+	if len(e.StackTrace()) == 0 || e.SpawnStackTrace() != nil {
+		t.Errorf("AsError: got stack %v, spawn stack %v", e.StackTrace(), e.SpawnStackTrace())
+	}
+
+	var target *Error
+	if !As(err, &target) || target != e {
+		t.Errorf("As(%v, *Error): got %v, want %v", err, target, e)
+	}
+	if _, ok := AsError(io.EOF); ok {
+		t.Errorf("AsError(EOF): got true, want false")
+	}
+}
+
+func TestMessageFallback(t *testing.T) {
+	defer func() { MessageFallback = nil }()
+
+	tests := []struct {
+		fallback func(string) string
+		err      func() error
+		want     string
+	}{
+		{nil, func() error { return NewCode("test.fallback") }, "test.fallback - "},
+		{CodeAsMessage, func() error { return NewCode("test.fallback") }, "test.fallback - test.fallback"},
+		{UnknownErrorMessage, func() error { return WrapCode(io.EOF, "test.fallback") }, "test.fallback - unknown error: EOF"},
+		{func(code string) string { return "see " + code }, func() error { return NewCode("test.fallback") }, "test.fallback - see test.fallback"},
+		{UnknownErrorMessage, func() error { return NewCode("test.fallback", "explicit") }, "test.fallback - explicit"},
+	}
+
+	for i, tt := range tests {
+		MessageFallback = tt.fallback
+		if got := tt.err().Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestMultiErrorChains(t *testing.T) {
+	Register(testCoder{code: "test.multi.inner", status: 409})
+
+	err := WrapCode(joined{
+		io.EOF,
+		NewCode("test.multi.inner", "save"),
+		joined{NewCode("test.multi.deep")},
+	}, "test.multi.outer")
+
+	for _, code := range []string{"test.multi.outer", "test.multi.inner", "test.multi.deep"} {
+		if !HasCode(err, code) {
+			t.Errorf("HasCode(%q): got false, want true", code)
+		}
+	}
+	if HasCode(err, "test.multi.missing") {
+		t.Errorf("HasCode(missing): got true, want false")
+	}
+	if !HasAnyCode(joined{io.EOF, NewCode("test.multi.deep")}, "test.multi.deep") {
+		t.Errorf("HasAnyCode(joined): got false, want true")
+	}
+	if !HasCodePrefix(joined{NewCode("test.multi.deep")}, "test.multi.") {
+		t.Errorf("HasCodePrefix(joined): got false, want true")
+	}
+
+	want := []string{"test.multi.outer", "test.multi.inner", "test.multi.deep"}
+	if got := Codes(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Codes(): got %v, want %v", got, want)
+	}
+	if got := Codes(io.EOF); got != nil {
+		t.Errorf("Codes(EOF): got %v, want nil", got)
+	}
+
+	if c := ParseCoder(joined{io.EOF, NewCode("test.multi.inner")}); c == nil || c.Code() != "test.multi.inner" {
+		t.Errorf("ParseCoder(joined): got %v, want test.multi.inner", c)
+	}
+
+	got := fmt.Sprintf("%+v", err)
+	for _, part := range []string{"EOF\n", "test.multi.inner - save\n", "test.multi.deep - "} {
+		if !strings.Contains(got, part) {
+			t.Errorf("%%+v does not contain %q:\n%s", part, got)
+		}
+	}
+}
+
+// mutableError is an error whose text changes, to observe caching.
+type mutableError struct{ text *string }
+
+func (e mutableError) Error() string { return *e.text }
+
+func TestCacheErrorText(t *testing.T) {
+	text := "before"
+	err := WrapCode(mutableError{&text}, "test.cache")
+	if got, want := err.Error(), "test.cache - : before"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+
+	text = "after"
+	if got, want := err.Error(), "test.cache - : before"; got != want {
+		t.Errorf("cached Error(): got %q, want %q", got, want)
+	}
+	if got, want := ReplaceCause(err, io.EOF).Error(), "test.cache - : EOF"; got != want {
+		t.Errorf("ReplaceCause().Error(): got %q, want %q", got, want)
+	}
+
+	LazyMessages = true
+	lazy := WrapCode(mutableError{&text}, "test.cache")
+	LazyMessages = false
+	_ = lazy.Error()
+	text = "changed"
+	if got, want := lazy.Error(), "test.cache - : changed"; got != want {
+		t.Errorf("lazy Error(): got %q, want %q", got, want)
+	}
+
+	CacheErrorText = false
+	defer func() { CacheErrorText = true }()
+	uncached := WrapCode(mutableError{&text}, "test.cache")
+	_ = uncached.Error()
+	text = "uncached"
+	if got, want := uncached.Error(), "test.cache - : uncached"; got != want {
+		t.Errorf("uncached Error(): got %q, want %q", got, want)
+	}
+}
+
+func TestCodeMatchingAllocs(t *testing.T) {
+	err := WrapCode(WrapCode(NewCode("test.allocs.root"), "test.allocs.mid"), "test.allocs.outer")
+	wrapped := Wrap(err, "annotated")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		IsCode(err, "test.allocs.missing")
+		HasCode(err, "test.allocs.missing")
+		HasCode(wrapped, "test.allocs.missing")
+		HasAnyCode(err, "test.allocs.missing", "test.allocs.other")
+		HasCodePrefix(err, "test.missing.")
+	})
+	if allocs != 0 {
+		t.Errorf("code matching allocates %v times, want 0", allocs)
+	}
+}
+
+func TestHasCodeDepth(t *testing.T) {
+	saved := MaxChainDepth
+	MaxChainDepth = 3
+	defer func() { MaxChainDepth = saved }()
+
+	err := WrapCode(WrapCode(WrapCode(NewCode("test.depth.root"), "test.depth"), "test.depth"), "test.depth")
+	if HasCode(err, "test.depth.root") {
+		t.Errorf("HasCode beyond MaxChainDepth: got true, want false")
+	}
+	if !HasCode(WrapCode(WrapCode(NewCode("test.depth.root"), "test.depth"), "test.depth"), "test.depth.root") {
+		t.Errorf("HasCode within MaxChainDepth: got false, want true")
+	}
+}
+
+func TestRegisterAll(t *testing.T) {
+	Register(testCoder{code: "test.batch.existing"})
+	Register(numCoder{testCoder{code: "test.batch.num"}, 94001})
+	if err := ReserveRange("test.batch.reserved.", "test.batch.reserved.", "billing"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { reservations = nil }()
+
+	err := RegisterAll(
+		testCoder{code: "test.batch.a"},
+		testCoder{code: "test.batch.existing"},
+		nil,
+		testCoder{code: ""},
+		testCoder{code: "test.batch.a"},
+		numCoder{testCoder{code: "test.batch.b"}, 94001},
+		numCoder{testCoder{code: "test.batch.c"}, 94002},
+		numCoder{testCoder{code: "test.batch.d"}, 94002},
+		testCoder{code: "test.batch.reserved.x"},
+	)
+
+	agg, ok := err.(Aggregate)
+	if !ok {
+		t.Fatalf("RegisterAll: got %v, want an Aggregate", err)
+	}
+	want := []string{
+		"code: test.batch.existing already exist",
+		"code: coder 2 is nil",
+		"code: coder 3 has an empty code",
+		"code: test.batch.a is registered twice",
+		"code: numeric code 94001 of test.batch.b already used by test.batch.num",
+		"code: numeric code 94002 of test.batch.d already used by test.batch.c",
+		"code: test.batch.reserved.x is reserved for billing",
+	}
+	if got := agg.Errors(); len(got) != len(want) {
+		t.Fatalf("RegisterAll: got %d failures, want %d: %v", len(got), len(want), got)
+	}
+	for i, err := range agg.Errors() {
+		if err.Error() != want[i] {
+			t.Errorf("failure %d: got %q, want %q", i+1, err, want[i])
+		}
+	}
+	for _, code := range []string{"test.batch.a", "test.batch.c"} {
+		if GetCoder(code) != nil {
+			t.Errorf("RegisterAll: %s registered by a failed batch", code)
+		}
+	}
+
+	if err := RegisterAll(testCoder{code: "test.batch.a"}, numCoder{testCoder{code: "test.batch.c"}, 94002}); err != nil {
+		t.Fatalf("RegisterAll: %v", err)
+	}
+	if GetCoder("test.batch.a") == nil || GetCoderByNum(94002) == nil {
+		t.Errorf("RegisterAll: batch not registered")
+	}
+}
+
+func TestCodesWithStatus(t *testing.T) {
+	Register(testCoder{code: "test.status.b", status: 499})
+	Register(testCoder{code: "test.status.a", status: 499})
+
+	if got, want := CodesWithStatus(499), []string{"test.status.a", "test.status.b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CodesWithStatus: got %v, want %v", got, want)
+	}
+	if got := CodesWithStatus(498); got != nil {
+		t.Errorf("CodesWithStatus: got %v, want nil", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Package codec encodes errors in the wire format of package errors with
+// compact binary encodings, MessagePack and CBOR, for services that do not
+// want to pay the overhead of JSON for error payloads.
+//
+// The decoders apply the same limits as errors.FromJSON and are safe to use
+// on payloads from untrusted sources.
+package codec
+
+import (
+	"bytes"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/pkg/errors"
+)
+
+// MarshalMsgpack encodes err's chain as MessagePack. Field names are the
+// same as in the JSON wire format.
+func MarshalMsgpack(err error) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(errors.ToWire(err)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMsgpack decodes an error chain encoded by MarshalMsgpack.
+func UnmarshalMsgpack(data []byte) (error, error) {
+	if len(data) > errors.MaxWireBytes {
+		return nil, errors.Errorf("codec: payload of %d bytes exceeds %d bytes", len(data), errors.MaxWireBytes)
+	}
+
+	var w *errors.Wire
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(&w); err != nil {
+		return nil, errors.Wrap(err, "codec: invalid msgpack payload")
+	}
+
+	return errors.FromWire(w)
+}
+
+// cborEnc encodes time.Time params as RFC 3339 strings, which keep the
+// precision and are restored by errors.FromWire.
+var cborEnc, _ = cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+
+// MarshalCBOR encodes err's chain as CBOR. Field names are the same as in
+// the JSON wire format.
+func MarshalCBOR(err error) ([]byte, error) {
+	return cborEnc.Marshal(errors.ToWire(err))
+}
+
+// UnmarshalCBOR decodes an error chain encoded by MarshalCBOR.
+func UnmarshalCBOR(data []byte) (error, error) {
+	if len(data) > errors.MaxWireBytes {
+		return nil, errors.Errorf("codec: payload of %d bytes exceeds %d bytes", len(data), errors.MaxWireBytes)
+	}
+
+	var w *errors.Wire
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return nil, errors.Wrap(err, "codec: invalid cbor payload")
+	}
+
+	return errors.FromWire(w)
+}
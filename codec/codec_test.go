@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(error) ([]byte, error)
+		unmarshal func([]byte) (error, error)
+	}{
+		{"msgpack", MarshalMsgpack, UnmarshalMsgpack},
+		{"cbor", MarshalCBOR, UnmarshalCBOR},
+	}
+
+	tests := []error{
+		nil,
+		errors.New("root"),
+		errors.NewCodeWithParams("test.codec", map[string]interface{}{"id": "7"}, "coded"),
+		errors.NewCodeWithParams("test.codec", map[string]interface{}{"n": int64(1<<62 + 1), "d": time.Second, "at": time.Unix(1e9, 0).UTC()}, "typed"),
+		errors.NewCodeWithParams("test.codec", map[string]interface{}{"n": 42, "small": int8(-3), "u": uint16(7)}, "small"),
+		errors.WithMessage(errors.WrapCode(errors.Wrap(io.EOF, "read"), "test.codec", "outer"), "ctx"),
+	}
+
+	for _, c := range codecs {
+		for i, want := range tests {
+			data, err := c.marshal(want)
+			if err != nil {
+				t.Fatalf("%s test %d: marshal: %v", c.name, i+1, err)
+			}
+			got, err := c.unmarshal(data)
+			if err != nil {
+				t.Fatalf("%s test %d: unmarshal: %v", c.name, i+1, err)
+			}
+			if !errors.EquivalentForTest(got, want) && (got == nil || want == nil || got.Error() != want.Error()) {
+				t.Errorf("%s test %d: got %v, want %v", c.name, i+1, got, want)
+			}
+			if !reflect.DeepEqual(utcParams(got), utcParams(want)) {
+				t.Errorf("%s test %d: params: got %#v, want %#v", c.name, i+1, errors.Params(got), errors.Params(want))
+			}
+		}
+
+		if _, err := c.unmarshal([]byte{0xff, 0x00}); err == nil {
+			t.Errorf("%s: unmarshal of invalid payload: want error", c.name)
+		}
+	}
+}
+
+// utcParams returns the params of err with times in UTC, as msgpack decodes
+// times in the local time zone.
+func utcParams(err error) map[string]interface{} {
+	params := errors.Params(err)
+	for k, v := range params {
+		if t, ok := v.(time.Time); ok {
+			params[k] = t.UTC()
+		}
+	}
+	return params
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Describer is implemented by Coders that document their code with a long
+// form description, such as when the error occurs and how to resolve it.
+// WriteCodeDocs uses it to document the generated constants.
+type Describer interface {
+	Description() string
+}
+
+// WriteCodeDocs writes to w the Go source of a file of package pkg that
+// declares a documented constant for each registered code, so that editors
+// show what a code means on hover. It is meant to be run by a small program
+// invoked with go generate, after the codes have been registered:
+//
+//     //go:generate go run ./gen
+//
+//     func main() {
+//             f, _ := os.Create("codes_gen.go")
+//             defer f.Close()
+//             if err := errors.WriteCodeDocs(f, "apperrors"); err != nil {
+//                     log.Fatal(err)
+//             }
+//     }
+//
+// The name of a constant is the code in camel case, for example
+// UserNotFound for "user.not_found". Its doc comment is the Description of
+// the Coder, if it implements Describer, or its message otherwise, followed
+// by the HTTP status and reference. Deprecated and retired codes are marked
+// as deprecated, so that linters flag their use.
+// WriteCodeDocs returns an error if two codes map to the same name.
+func WriteCodeDocs(w io.Writer, pkg string) error {
+	codeMux.RLock()
+	coders := make([]Coder, 0, len(codes))
+	for _, coder := range codes {
+		coders = append(coders, coder)
+	}
+	codeMux.RUnlock()
+
+	sort.Slice(coders, func(i, j int) bool {
+		return coders[i].Code() < coders[j].Code()
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by errors.WriteCodeDocs. DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	names := map[string]string{}
+	for _, coder := range coders {
+		code := coder.Code()
+		name := codeIdent(code)
+		if other, ok := names[name]; ok {
+			return Errorf("errors: codes %q and %q are both named %s", other, code, name)
+		}
+		names[name] = code
+
+		doc := coder.Message()
+		if d, ok := coder.(Describer); ok && d.Description() != "" {
+			doc = d.Description()
+		}
+
+		fmt.Fprintf(&buf, "\n// %s is the %q error code.\n", name, code)
+		if doc = strings.TrimSpace(doc); doc != "" {
+			buf.WriteString("//\n")
+			for _, line := range strings.Split(doc, "\n") {
+				buf.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+			}
+		}
+		fmt.Fprintf(&buf, "//\n// HTTP status: %d.\n", coder.StatusCode())
+		if ref := coder.Reference(); ref != "" {
+			fmt.Fprintf(&buf, "// Reference: %s\n", ref)
+		}
+		if l := lifecycleOf(coder); l == LifecycleDeprecated || l == LifecycleRetired {
+			fmt.Fprintf(&buf, "//\n// Deprecated: the code is %s.\n", l)
+		}
+		fmt.Fprintf(&buf, "const %s = %q\n", name, code)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return Wrap(err, "errors: format generated code docs")
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// codeIdent returns the exported Go identifier of code: its letters and
+// digits in camel case, starting a new word after any other character.
+func codeIdent(code string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range code {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteString("Code")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	if b.Len() == 0 {
+		return "Code"
+	}
+	return b.String()
+}
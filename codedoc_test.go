@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+type describerCoder struct {
+	testCoder
+	description string
+}
+
+func (c describerCoder) Description() string { return c.description }
+
+// withRegistry runs fn with only coders registered.
+func withRegistry(fn func(), coders ...Coder) {
+	codeMux.Lock()
+	saved := codes
+	codes = map[string]Coder{}
+	for _, c := range coders {
+		codes[c.Code()] = c
+	}
+	codeMux.Unlock()
+
+	defer func() {
+		codeMux.Lock()
+		codes = saved
+		codeMux.Unlock()
+	}()
+
+	fn()
+}
+
+func TestWriteCodeDocs(t *testing.T) {
+	var buf bytes.Buffer
+	var err error
+	withRegistry(func() {
+		err = WriteCodeDocs(&buf, "apperrors")
+	},
+		testCoder{code: "user.not_found", status: http.StatusNotFound, message: "user not found"},
+		describerCoder{testCoder{code: "payment.declined", status: http.StatusPaymentRequired}, "The card issuer declined the charge.\nAsk the user for another card."},
+		testCoder{code: "2fa-required", status: http.StatusForbidden},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// Code generated by errors.WriteCodeDocs. DO NOT EDIT.
+
+package apperrors
+
+// Code2faRequired is the "2fa-required" error code.
+//
+// HTTP status: 403.
+const Code2faRequired = "2fa-required"
+
+// PaymentDeclined is the "payment.declined" error code.
+//
+// The card issuer declined the charge.
+// Ask the user for another card.
+//
+// HTTP status: 402.
+const PaymentDeclined = "payment.declined"
+
+// UserNotFound is the "user.not_found" error code.
+//
+// user not found
+//
+// HTTP status: 404.
+const UserNotFound = "user.not_found"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCodeDocs:\n got %s\n want %s", got, want)
+	}
+
+	withRegistry(func() {
+		err = WriteCodeDocs(&buf, "apperrors")
+	}, testCoder{code: "user.not_found"}, testCoder{code: "user_not.found"})
+	if err == nil {
+		t.Errorf("WriteCodeDocs with colliding names: want error")
+	}
+}
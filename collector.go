@@ -0,0 +1,66 @@
+package errors
+
+import "sync"
+
+// Collector accumulates the errors and warnings of a multi-step operation,
+// such as a batch job, that continues after a step fails.
+// A Collector is safe for concurrent use.
+type Collector struct {
+	mu       sync.Mutex
+	errs     []error
+	warnings []error
+}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records err as a failure. Nil errors are ignored.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs = append(c.errs, err)
+}
+
+// Warn records err as a non-fatal warning. Warnings do not make Err return
+// an error. Nil errors are ignored.
+func (c *Collector) Warn(err error) {
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.warnings = append(c.warnings, err)
+}
+
+// Warnings returns the recorded warnings.
+func (c *Collector) Warnings() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]error(nil), c.warnings...)
+}
+
+// Err returns nil if no failure was recorded, the failure itself if exactly
+// one was recorded, and an Aggregate of all failures otherwise.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch len(c.errs) {
+	case 0:
+		return nil
+	case 1:
+		return c.errs[0]
+	}
+
+	return NewAggregate(c.errs)
+}
@@ -0,0 +1,115 @@
+// +build go1.13
+
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+)
+
+// CanceledCode and DeadlineCode are the canonical codes used by
+// ClassifyContext for canceled requests and requests whose deadline was
+// exceeded. They are also recognized by IsCanceled and IsDeadline.
+var (
+	CanceledCode = "request.canceled"
+	DeadlineCode = "request.timeout"
+)
+
+// ElapsedParam and TimeoutParam are the params set by WithTimeoutCode to
+// the time the operation ran for and the timeout it was given, formatted as
+// by time.Duration.String.
+const (
+	ElapsedParam = "elapsed"
+	TimeoutParam = "timeout"
+)
+
+// IsCanceled reports whether err's chain contains context.Canceled or an
+// error coded with CanceledCode.
+func IsCanceled(err error) bool {
+	return stderrors.Is(err, context.Canceled) || Match(err, func(code string, _ Coder) bool {
+		return code == CanceledCode
+	})
+}
+
+// IsDeadline reports whether err's chain contains context.DeadlineExceeded
+// or an error coded with DeadlineCode.
+func IsDeadline(err error) bool {
+	return stderrors.Is(err, context.DeadlineExceeded) || Match(err, func(code string, _ Coder) bool {
+		return code == DeadlineCode
+	})
+}
+
+// ClassifyContext annotates context errors in err's chain with CanceledCode
+// or DeadlineCode. Errors that already carry one of these codes, or that are
+// not caused by a context, are returned unchanged.
+// If err is nil, ClassifyContext returns nil.
+func ClassifyContext(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := ""
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		code = CanceledCode
+	case stderrors.Is(err, context.DeadlineExceeded):
+		code = DeadlineCode
+	default:
+		return err
+	}
+
+	if HasCode(err, code) {
+		return err
+	}
+
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(nil),
+		source:  messageSourceOf(nil),
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// WithTimeoutCode runs fn with a context derived from ctx that is canceled
+// after d. If fn returns an error caused by an exceeded deadline, it is
+// annotated with the supplied code and message, the ElapsedParam and
+// TimeoutParam params and the params added to ctx by Decorate:
+//
+//     err := errors.WithTimeoutCode(ctx, time.Second, "payment.timeout", func(ctx context.Context) error {
+//             return gateway.Charge(ctx, order)
+//     })
+//
+// Other errors returned by fn are returned unchanged.
+func WithTimeoutCode(ctx context.Context, d time.Duration, code string, fn func(ctx context.Context) error, msgs ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	if err == nil || !stderrors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	params := ctxParams(ctx, map[string]interface{}{
+		ElapsedParam: time.Since(start).String(),
+		TimeoutParam: d.String(),
+	})
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  params,
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
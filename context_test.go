@@ -0,0 +1,93 @@
+// +build go1.13
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsCanceled(t *testing.T) {
+	tests := []struct {
+		err      error
+		canceled bool
+		deadline bool
+	}{
+		{nil, false, false},
+		{New("plain"), false, false},
+		{Wrap(context.Canceled, "query"), true, false},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), false, true},
+		{WithMessage(NewCode(CanceledCode), "ctx"), true, false},
+		{WrapCode(New("slow"), DeadlineCode), false, true},
+	}
+
+	for i, tt := range tests {
+		if got := IsCanceled(tt.err); got != tt.canceled {
+			t.Errorf("test %d: IsCanceled(%v): got %v, want %v", i+1, tt.err, got, tt.canceled)
+		}
+		if got := IsDeadline(tt.err); got != tt.deadline {
+			t.Errorf("test %d: IsDeadline(%v): got %v, want %v", i+1, tt.err, got, tt.deadline)
+		}
+	}
+}
+
+func TestClassifyContext(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(context.Canceled, "query"), CanceledCode},
+		{context.DeadlineExceeded, DeadlineCode},
+		{New("plain"), ""},
+	}
+
+	for i, tt := range tests {
+		if got := Code(ClassifyContext(tt.err)); got != tt.want {
+			t.Errorf("test %d: ClassifyContext(%v): got code %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	err := ClassifyContext(context.Canceled)
+	if got := ClassifyContext(err); got != err {
+		t.Errorf("ClassifyContext: got %v, want unchanged %v", got, err)
+	}
+	if ClassifyContext(nil) != nil {
+		t.Errorf("ClassifyContext(nil): want nil")
+	}
+}
+
+func TestWithTimeoutCode(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return Wrap(ctx.Err(), "charge")
+	}
+
+	err := WithTimeoutCode(context.Background(), time.Millisecond, "test.timeout", slow, "gateway")
+	if got := Code(err); got != "test.timeout" {
+		t.Errorf("Code(): got %q, want %q", got, "test.timeout")
+	}
+	if !IsDeadline(err) {
+		t.Errorf("IsDeadline(%v): got false, want true", err)
+	}
+	params := Params(err)
+	if got := params[TimeoutParam]; got != "1ms" {
+		t.Errorf("Params()[%q]: got %v, want %q", TimeoutParam, got, "1ms")
+	}
+	if elapsed, err := time.ParseDuration(params[ElapsedParam].(string)); err != nil || elapsed < time.Millisecond {
+		t.Errorf("Params()[%q]: got %v", ElapsedParam, params[ElapsedParam])
+	}
+
+	root := New("declined")
+	if got := WithTimeoutCode(context.Background(), time.Second, "test.timeout", func(context.Context) error {
+		return root
+	}); got != root {
+		t.Errorf("WithTimeoutCode(declined): got %v, want %v", got, root)
+	}
+	if got := WithTimeoutCode(context.Background(), time.Second, "test.timeout", func(context.Context) error {
+		return nil
+	}); got != nil {
+		t.Errorf("WithTimeoutCode(nil): got %v, want nil", got)
+	}
+}
@@ -0,0 +1,64 @@
+package errors
+
+// AttemptParam is the name of the param holding the number of times a
+// message has been delivered, as read by ShouldDeadLetter.
+const AttemptParam = "attempt"
+
+// MaxDeliveryAttempts is the number of delivery attempts after which
+// ShouldDeadLetter gives up on errors that are not permanent.
+var MaxDeliveryAttempts = 5
+
+// ShouldDeadLetter reports whether a message whose processing failed with
+// err should be moved to a dead-letter queue instead of being retried.
+// Errors with FlagPermanent are dead-lettered at once. Other errors are
+// retried until the attempt param, found on any coded error in err's chain,
+// reaches MaxDeliveryAttempts.
+// If err is nil, ShouldDeadLetter returns false.
+func ShouldDeadLetter(err error) bool {
+	if err == nil {
+		return false
+	}
+	if HasFlag(err, FlagPermanent) {
+		return true
+	}
+
+	attempt, ok := attemptOf(err)
+	return ok && attempt >= MaxDeliveryAttempts
+}
+
+// attemptOf returns the outermost attempt param in err's chain.
+func attemptOf(err error) (int, bool) {
+	attempt, found := 0, false
+	walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok {
+			attempt, found = intParam(wc.params[AttemptParam])
+		}
+
+		return !found
+	})
+
+	return attempt, found
+}
+
+// intParam converts a numeric param, which is a float64 after a JSON round
+// trip, to an int.
+func intParam(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestShouldDeadLetter(t *testing.T) {
+	Register(flagCoder{testCoder{code: "test.dlq.malformed"}, FlagPermanent})
+	Register(flagCoder{testCoder{code: "test.dlq.timeout"}, FlagRetryable})
+
+	attempt := func(n interface{}) map[string]interface{} {
+		return map[string]interface{}{AttemptParam: n}
+	}
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{NewCode("test.dlq.malformed"), true},
+		{Wrap(NewCode("test.dlq.malformed"), "decode"), true},
+		{NewCode("test.dlq.timeout"), false},
+		{NewCodeWithParams("test.dlq.timeout", attempt(4)), false},
+		{NewCodeWithParams("test.dlq.timeout", attempt(5)), true},
+		{WrapCode(NewCodeWithParams("test.dlq.timeout", attempt(5.0)), "test.dlq.consume"), true},
+		{WrapCodeWithParams(io.EOF, "test.dlq.consume", attempt("5")), false},
+	}
+
+	for i, tt := range tests {
+		if got := ShouldDeadLetter(tt.err); got != tt.want {
+			t.Errorf("test %d: ShouldDeadLetter(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
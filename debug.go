@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// debugInfo is the registry and statistics snapshot exposed by DebugVar and
+// DebugHandler.
+type debugInfo struct {
+	Codes []debugCode          `json:"codes"`
+	Stats map[string]CodeStats `json:"stats"`
+}
+
+type debugCode struct {
+	Code      string `json:"code"`
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	Reference string `json:"reference,omitempty"`
+}
+
+func debugSnapshot() debugInfo {
+	codeMux.RLock()
+	info := debugInfo{Codes: make([]debugCode, 0, len(codes))}
+	for _, coder := range codes {
+		info.Codes = append(info.Codes, debugCode{
+			Code:      coder.Code(),
+			Status:    coder.StatusCode(),
+			Message:   coder.Message(),
+			Reference: coder.Reference(),
+		})
+	}
+	codeMux.RUnlock()
+
+	sort.Slice(info.Codes, func(i, j int) bool {
+		return info.Codes[i].Code < info.Codes[j].Code
+	})
+	info.Stats = Stats()
+
+	return info
+}
+
+// DebugVar is an expvar.Var exposing the registered codes and the
+// statistics of Stats as JSON. Publish it with
+//
+//	expvar.Publish("errors", errors.DebugVar{})
+//
+// This package does not import expvar, so that it does not register the
+// /debug/vars handler on behalf of its users.
+type DebugVar struct{}
+
+func (DebugVar) String() string {
+	data, err := json.Marshal(debugSnapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(data)
+}
+
+// DebugHandler returns an http.Handler that responds with the registered
+// codes and the statistics of Stats as JSON. It is meant for a debug port:
+//
+//	mux.Handle("/debug/errors", errors.DebugHandler())
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(debugSnapshot())
+	})
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	Register(testCoder{code: "test.debug", status: http.StatusConflict, message: "conflict"})
+
+	CollectStats = true
+	defer func() { CollectStats = false }()
+	defer ResetStats()
+	NewCode("test.debug")
+
+	w := httptest.NewRecorder()
+	DebugHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+
+	for name, data := range map[string][]byte{"DebugHandler": w.Body.Bytes(), "DebugVar": []byte(DebugVar{}.String())} {
+		var info debugInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		found := false
+		for _, c := range info.Codes {
+			if c.Code == "test.debug" {
+				found = c.Status == http.StatusConflict && c.Message == "conflict"
+			}
+		}
+		if !found {
+			t.Errorf("%s: test.debug not listed in %s", name, data)
+		}
+		if info.Stats["test.debug"].Created != 1 {
+			t.Errorf("%s: got stats %v, want 1 created", name, info.Stats["test.debug"])
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+)
+
+type decorationKey struct{}
+
+// Decorate returns a copy of ctx carrying the params in kv, which holds
+// alternating keys and values. The params are added to those of earlier
+// calls, replacing params with the same key. Errors created by the
+// constructors taking a context, such as WrapCodeCtx, inherit them, so that
+// ambient fields set near the top of a request reach errors created deep in
+// the stack:
+//
+//     ctx = errors.Decorate(ctx, "tenant", tenant, "region", region)
+//
+// Keys that are not strings are formatted with fmt.Sprint. A final key
+// without a value is ignored.
+func Decorate(ctx context.Context, kv ...interface{}) context.Context {
+	parent := decorations(ctx)
+
+	params := make(map[string]interface{}, len(parent)+len(kv)/2)
+	for k, v := range parent {
+		params[k] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		k, ok := kv[i].(string)
+		if !ok {
+			k = fmt.Sprint(kv[i])
+		}
+		params[k] = kv[i+1]
+	}
+
+	return context.WithValue(ctx, decorationKey{}, params)
+}
+
+func decorations(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	params, _ := ctx.Value(decorationKey{}).(map[string]interface{})
+	return params
+}
+
+// ctxParams returns the params of an error created with ctx: the
+// decorations of ctx, the trace params of ctx and params, in increasing
+// order of precedence. It returns nil if there are none.
+func ctxParams(ctx context.Context, params map[string]interface{}) map[string]interface{} {
+	decorated, traced := decorations(ctx), traceParams(ctx)
+	if len(decorated) == 0 && len(traced) == 0 {
+		return params
+	}
+
+	merged := make(map[string]interface{}, len(decorated)+len(traced)+len(params))
+	for _, m := range []map[string]interface{}{decorated, traced, params} {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
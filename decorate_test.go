@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDecorate(t *testing.T) {
+	saved := TraceIDs
+	TraceIDs = func(ctx context.Context) (string, string) {
+		ids, _ := ctx.Value(traceKey{}).([2]string)
+		return ids[0], ids[1]
+	}
+	defer func() { TraceIDs = saved }()
+
+	top := Decorate(context.Background(), "tenant", "acme", "region", "eu")
+	ctx := Decorate(top, "region", "us", 7, "seven", "dangling")
+
+	err := WrapCodeCtx(ctx, New("root"), "test.decorate")
+	want := map[string]interface{}{"tenant": "acme", "region": "us", "7": "seven"}
+	if got := Params(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params: got %v, want %v", got, want)
+	}
+
+	err = WrapCodeCtx(top, New("root"), "test.decorate")
+	want = map[string]interface{}{"tenant": "acme", "region": "eu"}
+	if got := Params(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params of parent: got %v, want %v", got, want)
+	}
+
+	traced := context.WithValue(Decorate(context.Background(), "trace_id", "ambient", "tenant", "acme"), traceKey{}, [2]string{"t-1", ""})
+	err = WrapCodeCtx(traced, New("root"), "test.decorate")
+	want = map[string]interface{}{"tenant": "acme", "trace_id": "t-1"}
+	if got := Params(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params with trace: got %v, want %v", got, want)
+	}
+}
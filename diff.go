@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff describes how a and b differ, one difference per line, walking their
+// chains in parallel: the codes, messages and params of coded errors, the
+// messages of other errors, and errors present in only one chain. Stack
+// traces and instance IDs are ignored, as by EquivalentForTest, and Diff
+// returns the empty string for equivalent errors. It is meant for the
+// failure messages of tests:
+//
+//     if d := errors.Diff(err, want); d != "" {
+//             t.Errorf("unexpected error (-got +want):\n%s", d)
+//     }
+//
+// Differences in a cause are prefixed by its depth, such as "cause 1: ".
+func Diff(a, b error) string {
+	var lines []string
+	for depth := 0; depth < MaxChainDepth; depth++ {
+		a, b = skipStack(a), skipStack(b)
+		if a == nil && b == nil {
+			break
+		}
+
+		prefix := ""
+		if depth > 0 {
+			prefix = fmt.Sprintf("cause %d: ", depth)
+		}
+		add := func(format string, args ...interface{}) {
+			lines = append(lines, prefix+fmt.Sprintf(format, args...))
+		}
+
+		x, xok := a.(*Error)
+		y, yok := b.(*Error)
+		switch {
+		case a == nil || b == nil:
+			add("%s != %s", diffText(a), diffText(b))
+		case xok && yok:
+			if x.code != y.code {
+				add("code: %q != %q", x.code, y.code)
+			}
+			if xm, ym := x.Message(), y.Message(); xm != ym {
+				add("message: %q != %q", xm, ym)
+			}
+			for _, key := range diffKeys(x.params, y.params) {
+				xv, xin := x.params[key]
+				yv, yin := y.params[key]
+				if xin != yin || !reflect.DeepEqual(xv, yv) {
+					add("params[%q]: %s != %s", key, diffValue(xv, xin), diffValue(yv, yin))
+				}
+			}
+			a, b = x.cause, y.cause
+			continue
+		case xok || yok:
+			add("%s != %s", diffText(a), diffText(b))
+		default:
+			xm, xnext := diffLayer(a)
+			ym, ynext := diffLayer(b)
+			if xm != ym {
+				add("message: %q != %q", xm, ym)
+			}
+			if xnext != nil || ynext != nil {
+				a, b = xnext, ynext
+				continue
+			}
+		}
+
+		break
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// diffLayer returns the message of the uncoded error err and the cause Diff
+// continues with, if err's message does not include the cause's.
+func diffLayer(err error) (string, error) {
+	switch e := err.(type) {
+	case *withMessage:
+		return e.msg, e.cause
+	case *fundamental:
+		return e.msg, nil
+	case *withRetryAfter:
+		return fmt.Sprintf("retry after %v", e.after), e.error
+	case *withUpstream:
+		return fmt.Sprintf("upstream %+v", e.upstream), e.error
+	}
+
+	return err.Error(), nil
+}
+
+// diffText describes err when it is missing from the other chain or differs
+// in kind: its code and message, its text, or <nil>.
+func diffText(err error) string {
+	switch e := err.(type) {
+	case nil:
+		return "<nil>"
+	case *Error:
+		return fmt.Sprintf("code %q with message %q", e.code, e.Message())
+	}
+
+	return fmt.Sprintf("%q", err.Error())
+}
+
+// diffValue formats a param value, or <missing> if the param is not set.
+func diffValue(v interface{}, ok bool) string {
+	if !ok {
+		return "<missing>"
+	}
+
+	return fmt.Sprintf("%#v", v)
+}
+
+// diffKeys returns the sorted union of the keys of a and b.
+func diffKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b error
+		want string
+	}{
+		{nil, nil, ""},
+		{NewCode("test.diff", "m"), NewCode("test.diff", "m"), ""},
+		{WithStack(WrapCode(io.EOF, "test.diff")), WrapCode(io.EOF, "test.diff"), ""},
+		{NewCode("test.diff.a", "m"), NewCode("test.diff.b", "n"), "code: \"test.diff.a\" != \"test.diff.b\"\nmessage: \"m\" != \"n\""},
+		{
+			NewCodeWithParams("test.diff", map[string]interface{}{"id": 1, "user": "ann"}),
+			NewCodeWithParams("test.diff", map[string]interface{}{"id": 2, "org": "acme"}),
+			"params[\"id\"]: 1 != 2\nparams[\"org\"]: <missing> != \"acme\"\nparams[\"user\"]: \"ann\" != <missing>",
+		},
+		{
+			WrapCode(WrapCode(io.EOF, "test.diff.inner"), "test.diff"),
+			WrapCode(WrapCode(io.ErrUnexpectedEOF, "test.diff.other"), "test.diff"),
+			"cause 1: code: \"test.diff.inner\" != \"test.diff.other\"\ncause 2: message: \"EOF\" != \"unexpected EOF\"",
+		},
+		{WrapCode(io.EOF, "test.diff"), NewCode("test.diff"), "cause 1: \"EOF\" != <nil>"},
+		{Wrap(New("a"), "read"), Wrap(New("b"), "write"), "message: \"read\" != \"write\"\ncause 1: message: \"a\" != \"b\""},
+		{WithRetryAfter(io.EOF, time.Second), WithRetryAfter(io.EOF, time.Minute), "message: \"retry after 1s\" != \"retry after 1m0s\""},
+		{New("plain"), NewCode("test.diff", "m"), "\"plain\" != code \"test.diff\" with message \"m\""},
+	}
+
+	for i, tt := range tests {
+		if got := Diff(tt.a, tt.b); got != tt.want {
+			t.Errorf("test %d: Diff(%v, %v):\ngot  %q\nwant %q", i+1, tt.a, tt.b, got, tt.want)
+		}
+		if got := Diff(tt.a, tt.b) == ""; got != EquivalentForTest(tt.a, tt.b) {
+			t.Errorf("test %d: Diff is empty: %v, EquivalentForTest: %v", i+1, got, !got)
+		}
+	}
+}
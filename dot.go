@@ -0,0 +1,215 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ToDOT renders err's chain as a graph in the DOT language of Graphviz, to
+// visualize complex failure trees, for example those of errors loaded from
+// an incident dump with LoadError:
+//
+//     ioutil.WriteFile("err.dot", []byte(errors.ToDOT(err)), 0644)
+//     // dot -Tsvg err.dot > err.svg
+//
+// Each error of the chain is a node labeled with its code, its message and
+// the location where it was created, and points to its cause. Errors
+// wrapping several errors, such as aggregates and the errors of the
+// standard errors.Join, are diamond nodes pointing to each of their
+// members. Layers adding only a stack trace or an annotation, such as
+// WithStack and WithUpstream, are not drawn; the location of WithStack and
+// the upstream call of WithUpstream are given to the error they annotate.
+// An error reached from several branches is drawn once. Graphs with more
+// than MaxChainDepth nodes are truncated and the truncation is drawn as a
+// "..." node.
+// If err is nil, ToDOT returns an empty graph.
+func ToDOT(err error) string {
+	g := &dotGraph{ids: map[error]int{}, truncated: -1}
+	g.b.WriteString("digraph errors {\n\tnode [shape=box];\n")
+	g.add(err, 0)
+	g.b.WriteString("}\n")
+
+	return g.b.String()
+}
+
+// dotGraph is the graph being rendered by ToDOT.
+type dotGraph struct {
+	b         strings.Builder
+	ids       map[error]int // node IDs of the comparable errors drawn
+	nodes     int
+	truncated int // node ID of the truncation node, or -1
+}
+
+// add draws err and its causes, and returns the node ID of err, or -1 if
+// err is nil.
+func (g *dotGraph) add(err error, depth int) int {
+	location, upstream := "", ""
+	for ; err != nil; err = next(err) {
+		if depth >= MaxChainDepth {
+			return g.truncate()
+		}
+		depth++
+
+		switch e := err.(type) {
+		case *withStack:
+			if location == "" {
+				location = stackLocation(e.stack)
+			}
+			continue
+		case *withUpstream:
+			if upstream == "" {
+				upstream = upstreamLabel(e.upstream)
+			}
+			continue
+		case *withFrameNote, *withRetryAfter, *withoutCause:
+			continue
+		}
+		break
+	}
+	if err == nil {
+		return -1
+	}
+
+	comparable := reflect.TypeOf(err).Comparable()
+	if comparable {
+		if id, ok := g.ids[err]; ok {
+			return id
+		}
+	}
+	if g.nodes >= MaxChainDepth {
+		return g.truncate()
+	}
+
+	id := g.nodes
+	g.nodes++
+	if comparable {
+		g.ids[err] = id
+	}
+
+	if multi, ok := err.(multiWrapper); ok {
+		g.node(id, "join", "diamond")
+		for _, member := range multi.Unwrap() {
+			g.edge(id, g.add(member, depth))
+		}
+		return id
+	}
+
+	var lines []string
+	var cause error
+	switch e := err.(type) {
+	case *Error:
+		lines = []string{e.code, e.Message()}
+		if e.restored != nil {
+			location = restoredLocation(e.restored)
+		} else if l := stackLocation(e.stack); l != "" {
+			location = l
+		}
+		cause = e.cause
+	case *withMessage:
+		lines = []string{e.msg}
+		cause = e.cause
+	case *fundamental:
+		lines = []string{e.msg}
+		location = stackLocation(e.stack)
+	default:
+		lines = []string{err.Error()}
+		cause = next(err)
+	}
+	if location != "" {
+		lines = append(lines, location)
+	}
+	if upstream != "" {
+		lines = append(lines, upstream)
+	}
+
+	g.node(id, strings.Join(lines, "\n"), "")
+	g.edge(id, g.add(cause, depth))
+
+	return id
+}
+
+// truncate returns the node ID of the truncation node, drawing it first if
+// needed.
+func (g *dotGraph) truncate() int {
+	if g.truncated < 0 {
+		g.truncated = g.nodes
+		g.nodes++
+		g.node(g.truncated, "...", "plaintext")
+	}
+
+	return g.truncated
+}
+
+func (g *dotGraph) node(id int, label, shape string) {
+	fmt.Fprintf(&g.b, "\te%d [label=%s", id, dotQuote(label))
+	if shape != "" {
+		g.b.WriteString(", shape=" + shape)
+	}
+	g.b.WriteString("];\n")
+}
+
+func (g *dotGraph) edge(from, to int) {
+	if to >= 0 {
+		fmt.Fprintf(&g.b, "\te%d -> e%d;\n", from, to)
+	}
+}
+
+// stackLocation returns the function and file:line of the first frame of s,
+// or "" if s is empty.
+func stackLocation(s *stack) string {
+	if s == nil || len(*s) == 0 {
+		return ""
+	}
+
+	f := Frame((*s)[0])
+	return fmt.Sprintf("%s %v", f.name(), f)
+}
+
+// upstreamLabel describes the upstream call of a WithUpstream annotation.
+func upstreamLabel(u Upstream) string {
+	label := "upstream: " + u.Service
+	if u.Endpoint != "" {
+		label += " " + u.Endpoint
+	}
+	if u.Status != 0 {
+		label += " " + strconv.Itoa(u.Status)
+	}
+
+	return label
+}
+
+// restoredLocation returns the location of the first of the frames restored
+// by LoadError, as stackLocation does.
+func restoredLocation(frames []persistedFrame) string {
+	if len(frames) == 0 {
+		return ""
+	}
+
+	f := frames[0]
+	return f.Function + " " + path.Base(f.File) + ":" + strconv.Itoa(f.Line)
+}
+
+// dotQuote returns s as a DOT quoted string, with line breaks kept as
+// centered line breaks.
+func dotQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	if got, want := ToDOT(nil), "digraph errors {\n\tnode [shape=box];\n}\n"; got != want {
+		t.Errorf("ToDOT(nil): got %q, want %q", got, want)
+	}
+
+	shared := New(`disk "a" full`)
+	err := WrapCode(NewAggregate([]error{
+		WithUpstream(Wrap(shared, "write"), "storage", "PUT /blobs", 507),
+		WithMessage(shared, "flush"),
+	}), "test.dot", "save failed")
+
+	// Locations depend on the build, so only their file is compared.
+	got := regexp.MustCompile(`github\.com/pkg/errors\.TestToDOT dot_test\.go:\d+`).ReplaceAllString(ToDOT(err), "LOC")
+	want := `digraph errors {
+	node [shape=box];
+	e0 [label="test.dot\nsave failed\nLOC"];
+	e1 [label="join", shape=diamond];
+	e2 [label="write\nLOC\nupstream: storage PUT /blobs 507"];
+	e3 [label="disk \"a\" full\nLOC"];
+	e2 -> e3;
+	e1 -> e2;
+	e4 [label="flush"];
+	e4 -> e3;
+	e1 -> e4;
+	e0 -> e1;
+}
+`
+	if got != want {
+		t.Errorf("ToDOT:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToDOTCycle(t *testing.T) {
+	l := &loop{}
+	l.next = WithMessage(l, "again")
+
+	want := "digraph errors {\n\tnode [shape=box];\n\te0 [label=\"loop\"];\n\te1 [label=\"again\"];\n\te1 -> e0;\n\te0 -> e1;\n}\n"
+	if got := ToDOT(l); got != want {
+		t.Errorf("ToDOT: got %q, want %q", got, want)
+	}
+}
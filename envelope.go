@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Header names set by NewEnvelope. They are meant to be copied into the
+// headers of a Kafka or NATS message, so that consumers of a dead-letter
+// queue can route failed messages without decoding them.
+const (
+	HeaderCode      = "error-code"
+	HeaderSeverity  = "error-severity"
+	HeaderRetryable = "error-retryable"
+	HeaderTraceID   = "error-trace-id"
+	HeaderID        = "error-id"
+)
+
+// SeverityCoder is implemented by Coders that declare the severity of their
+// errors, such as "warning" or "critical".
+type SeverityCoder interface {
+	Severity() string
+}
+
+// Envelope carries a failed message together with the error it failed with,
+// for dead-letter queues and other asynchronous error channels.
+type Envelope struct {
+	// Headers describe the error; see the Header constants.
+	Headers map[string]string `json:"headers"`
+
+	// Error is the error chain in the wire format of ToWire.
+	Error *Wire `json:"error"`
+
+	// Payload is the original message, unchanged.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// NewEnvelope returns an Envelope for payload that failed with err.
+// The headers hold the outermost code, the severity of its Coder, falling
+// back to "error" for 5xx and "warning" for other status codes, whether the
+// error is retryable according to FlagRetryable or RetryAfter, the
+// "trace_id" param recorded by WrapCodeCtx and the instance ID.
+// Headers without a value are omitted.
+func NewEnvelope(err error, payload []byte) *Envelope {
+	e := &Envelope{
+		Headers: map[string]string{},
+		Error:   ToWire(err),
+		Payload: payload,
+	}
+
+	_, retryable := RetryAfter(err)
+	e.Headers[HeaderRetryable] = strconv.FormatBool(retryable || HasFlag(err, FlagRetryable))
+
+	wc := codeOf(err)
+	if wc == nil {
+		return e
+	}
+
+	e.Headers[HeaderCode] = wc.code
+	if coder := wc.Coder(); coder != nil {
+		severity := "warning"
+		if s, ok := coder.(SeverityCoder); ok {
+			severity = s.Severity()
+		} else if coder.StatusCode() >= http.StatusInternalServerError {
+			severity = "error"
+		}
+		e.Headers[HeaderSeverity] = severity
+	}
+	if traceID, ok := wc.params["trace_id"].(string); ok && traceID != "" {
+		e.Headers[HeaderTraceID] = traceID
+	}
+	if wc.id != "" {
+		e.Headers[HeaderID] = wc.id
+	}
+
+	return e
+}
+
+// Err decodes the error of e, as FromWire does.
+func (e *Envelope) Err() (error, error) {
+	return FromWire(e.Error)
+}
+
+// Marshal encodes e as JSON.
+func (e *Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalEnvelope decodes an Envelope encoded by Marshal.
+func UnmarshalEnvelope(data []byte) (*Envelope, error) {
+	var e Envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, Wrap(err, "errors: invalid envelope")
+	}
+
+	return &e, nil
+}
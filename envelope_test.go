@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type severityCoder struct {
+	testCoder
+	severity string
+}
+
+func (c severityCoder) Severity() string { return c.severity }
+
+func TestNewEnvelope(t *testing.T) {
+	Register(severityCoder{testCoder{code: "test.envelope.fatal", status: http.StatusBadRequest, message: "poison message"}, "critical"})
+	Register(testCoder{code: "test.envelope.down", status: http.StatusServiceUnavailable, message: "down"})
+
+	TraceIDs = func(ctx context.Context) (string, string) { return "t-1", "s-1" }
+	defer func() { TraceIDs = nil }()
+
+	tests := []struct {
+		err     error
+		headers map[string]string
+	}{{
+		err:     WrapCodeCtx(context.Background(), io.EOF, "test.envelope.fatal"),
+		headers: map[string]string{HeaderCode: "test.envelope.fatal", HeaderSeverity: "critical", HeaderRetryable: "false", HeaderTraceID: "t-1"},
+	}, {
+		err:     WithRetryAfter(NewCode("test.envelope.down"), time.Second),
+		headers: map[string]string{HeaderCode: "test.envelope.down", HeaderSeverity: "error", HeaderRetryable: "true"},
+	}, {
+		err:     io.EOF,
+		headers: map[string]string{HeaderRetryable: "false"},
+	}}
+
+	for i, tt := range tests {
+		e := NewEnvelope(tt.err, []byte("order-42"))
+		if !reflect.DeepEqual(e.Headers, tt.headers) {
+			t.Errorf("test %d: Headers: got %v, want %v", i+1, e.Headers, tt.headers)
+		}
+	}
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	data, err := NewEnvelope(WrapCode(io.EOF, "test.envelope.trip", "decode failed"), []byte{0, 1, 2}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := UnmarshalEnvelope(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Headers[HeaderCode]; got != "test.envelope.trip" {
+		t.Errorf("Headers: got code %q, want %q", got, "test.envelope.trip")
+	}
+	if !reflect.DeepEqual(e.Payload, []byte{0, 1, 2}) {
+		t.Errorf("Payload: got %v, want [0 1 2]", e.Payload)
+	}
+
+	decoded, err := e.Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.Error(), "test.envelope.trip - decode failed: EOF"; got != want {
+		t.Errorf("Err: got %q, want %q", got, want)
+	}
+
+	if _, err := UnmarshalEnvelope([]byte("{")); err == nil {
+		t.Errorf("UnmarshalEnvelope(invalid): got nil error")
+	}
+}
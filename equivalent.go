@@ -0,0 +1,82 @@
+package errors
+
+import "reflect"
+
+// EquivalentForTest reports whether a and b describe the same error,
+// ignoring the parts that differ between two otherwise identical errors:
+// stack traces and instance IDs. Coded errors are equivalent when their
+// codes, messages, params and causes are equivalent. It is meant for
+// table-driven tests asserting an expected error:
+//
+//     want := errors.WrapCode(io.EOF, "store.read")
+//     if !errors.EquivalentForTest(err, want) {
+//             t.Errorf("got %v, want %v", err, want)
+//     }
+func EquivalentForTest(a, b error) bool {
+	return equivalent(a, b, 0)
+}
+
+func equivalent(a, b error, depth int) bool {
+	a, b = skipStack(a), skipStack(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if depth >= MaxChainDepth {
+		return true
+	}
+
+	switch x := a.(type) {
+	case *Error:
+		y, ok := b.(*Error)
+		return ok && x.code == y.code && x.Message() == y.Message() &&
+			(len(x.params) == 0 && len(y.params) == 0 || reflect.DeepEqual(x.params, y.params)) &&
+			equivalent(x.cause, y.cause, depth+1)
+	case *withMessage:
+		y, ok := b.(*withMessage)
+		return ok && x.msg == y.msg && equivalent(x.cause, y.cause, depth+1)
+	case *fundamental:
+		y, ok := b.(*fundamental)
+		return ok && x.msg == y.msg
+	case *withRetryAfter:
+		y, ok := b.(*withRetryAfter)
+		return ok && x.after == y.after && equivalent(x.error, y.error, depth+1)
+	case *withUpstream:
+		y, ok := b.(*withUpstream)
+		return ok && x.upstream == y.upstream && equivalent(x.error, y.error, depth+1)
+	case aggregate:
+		y, ok := b.(aggregate)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equivalent(x[i], y[i], depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if reflect.TypeOf(a).Comparable() && a == b {
+		return true
+	}
+
+	return a.Error() == b.Error()
+}
+
+// skipStack returns the error annotated by the WithStack and AnnotateFrame
+// layers of err.
+func skipStack(err error) error {
+	for {
+		switch e := err.(type) {
+		case *withStack:
+			err = e.error
+		case *withFrameNote:
+			err = e.error
+		default:
+			return err
+		}
+	}
+}
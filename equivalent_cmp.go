@@ -0,0 +1,11 @@
+// +build gocmp
+
+package errors
+
+import "github.com/google/go-cmp/cmp"
+
+// EquateErrors returns a cmp.Option that compares errors with
+// EquivalentForTest, ignoring stack traces and instance IDs.
+func EquateErrors() cmp.Option {
+	return cmp.Comparer(EquivalentForTest)
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestEquivalentForTest(t *testing.T) {
+	params := map[string]interface{}{"id": 7}
+
+	tests := []struct {
+		a, b error
+		want bool
+	}{
+		{nil, nil, true},
+		{New("a"), nil, false},
+		{New("a"), New("a"), true},
+		{New("a"), New("b"), false},
+		{NewCode("test.eq", "msg"), NewCode("test.eq", "msg"), true},
+		{NewCode("test.eq", "msg"), NewCode("test.other", "msg"), false},
+		{NewCodeWithParams("test.eq", params), NewCodeWithParams("test.eq", map[string]interface{}{"id": 7}), true},
+		{NewCodeWithParams("test.eq", params), NewCodeWithParams("test.eq", map[string]interface{}{"id": 8}), false},
+		{NewCodeWithParams("test.eq", map[string]interface{}{}), NewCode("test.eq"), true},
+		{WrapCode(io.EOF, "test.eq"), WrapCode(io.EOF, "test.eq"), true},
+		{WrapCode(io.EOF, "test.eq"), WrapCode(io.ErrUnexpectedEOF, "test.eq"), false},
+		{Wrap(NewCode("test.eq"), "ctx"), WithMessage(NewCode("test.eq"), "ctx"), true},
+		{WithRetryAfter(New("a"), time.Second), WithRetryAfter(New("a"), time.Minute), false},
+		{NewAggregate([]error{New("a"), NewCode("test.eq")}), NewAggregate([]error{New("a"), NewCode("test.eq")}), true},
+		{NewAggregate([]error{New("a")}), NewAggregate([]error{New("a"), New("b")}), false},
+	}
+
+	for i, tt := range tests {
+		if got := EquivalentForTest(tt.a, tt.b); got != tt.want {
+			t.Errorf("test %d: EquivalentForTest(%v, %v): got %v, want %v", i+1, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package errcodelint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// constructors are the functions of the errors package that create a new
+// coded error value. NewSentinel is left out, as its sentinels are meant to
+// be compared by identity.
+var constructors = map[string]bool{
+	"NewCode":            true,
+	"NewCodef":           true,
+	"NewCodeWithParams":  true,
+	"NewCodeT":           true,
+	"FromCoder":          true,
+	"WrapCode":           true,
+	"WrapCodeWithParams": true,
+	"WrapCodeT":          true,
+	"WrapCodeCtx":        true,
+	"WrapCodeIf":         true,
+	"WrapUnlessCode":     true,
+	"NewBase":            true,
+	"NewBaseWithParams":  true,
+	"WrapBase":           true,
+	"Assert":             true,
+	"Assertf":            true,
+	"FromExec":           true,
+	"FromPanic":          true,
+	"Classify":           true,
+	"ClassifyContext":    true,
+	"ClassifyFS":         true,
+}
+
+// codedVar is the fact that a package level variable holds a coded error.
+type codedVar struct{}
+
+func (*codedVar) AFact() {}
+
+func (*codedVar) String() string { return "coded" }
+
+// CompareAnalyzer reports comparisons with coded errors by identity.
+// Coded errors are usually wrapped on their way up the call stack, so they
+// must be matched with errors.IsCode, errors.HasCode or errors.Is.
+var CompareAnalyzer = &analysis.Analyzer{
+	Name:      "errcodecompare",
+	Doc:       "report comparisons of errors with coded errors using == and !=",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(codedVar)},
+	Run:       runCompare,
+}
+
+func runCompare(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Local variables holding coded errors, recorded in source order.
+	coded := map[types.Object]bool{}
+	insp.Preorder([]ast.Node{(*ast.ValueSpec)(nil), (*ast.AssignStmt)(nil)}, func(n ast.Node) {
+		var names []ast.Expr
+		var values []ast.Expr
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				names = append(names, name)
+			}
+			values = n.Values
+		case *ast.AssignStmt:
+			names, values = n.Lhs, n.Rhs
+		}
+		if len(names) != len(values) {
+			return
+		}
+
+		for i, name := range names {
+			id, ok := name.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+			if !ok {
+				continue
+			}
+
+			isCoded := isCodedConstructor(pass, values[i])
+			if obj.Parent() == obj.Pkg().Scope() {
+				if isCoded {
+					pass.ExportObjectFact(obj, new(codedVar))
+				}
+				continue
+			}
+			coded[obj] = isCoded
+		}
+	})
+
+	isCodedExpr := func(e ast.Expr) bool {
+		if isCodedConstructor(pass, e) {
+			return true
+		}
+
+		var id *ast.Ident
+		switch e := e.(type) {
+		case *ast.Ident:
+			id = e
+		case *ast.SelectorExpr:
+			id = e.Sel
+		default:
+			return false
+		}
+
+		obj, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok {
+			return false
+		}
+
+		return coded[obj] || pass.ImportObjectFact(obj, new(codedVar))
+	}
+
+	insp.Preorder([]ast.Node{(*ast.BinaryExpr)(nil), (*ast.SwitchStmt)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ {
+				return
+			}
+			for _, operand := range []ast.Expr{n.X, n.Y} {
+				if isCodedExpr(operand) {
+					pass.Reportf(n.Pos(), "coded errors are not comparable by identity; use errors.IsCode or errors.Is")
+					return
+				}
+			}
+		case *ast.SwitchStmt:
+			if n.Tag == nil || !isError(pass.TypesInfo.TypeOf(n.Tag)) {
+				return
+			}
+			for _, stmt := range n.Body.List {
+				for _, e := range stmt.(*ast.CaseClause).List {
+					if isCodedExpr(e) {
+						pass.Reportf(e.Pos(), "coded errors are not comparable by identity; use errors.IsCode or errors.Is")
+					}
+				}
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// isCodedConstructor reports whether e is a call creating a coded error.
+func isCodedConstructor(pass *analysis.Pass, e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	fn := callee(pass, call)
+	return fn != nil && fn.Pkg() != nil && errorsPaths[fn.Pkg().Path()] && !isMethod(fn) && constructors[fn.Name()]
+}
@@ -0,0 +1,381 @@
+// Package errcodelint defines analyzers that check the use of coded errors
+// of the github.com/pkg/errors package.
+//
+// Analyzer reports
+//
+//     - string literal codes that are not listed in the registry file given
+//       with the -registry flag,
+//     - errors returned from calls into other packages without being
+//       annotated with a code, e.g. by WrapCode; annotations without a
+//       code, such as Wrap, do not count,
+//     - calls to IsCode on errors that may have been wrapped, where HasCode,
+//       which searches the whole chain, is usually intended.
+//
+// CompareAnalyzer reports comparisons of errors with coded errors using ==,
+// != or switch statements.
+package errcodelint
+
+import (
+	"bufio"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// errorsPaths are the import paths of the errors package.
+var errorsPaths = map[string]bool{
+	"github.com/pkg/errors":         true,
+	"github.com/haijianyang/errors": true,
+}
+
+// codeArgs maps the functions of the errors package that take a code to the
+// index of their code argument.
+var codeArgs = map[string]int{
+	"NewCode":            0,
+	"NewCodeWithParams":  0,
+	"WrapCode":           1,
+	"WrapCodeWithParams": 1,
+	"WrapCodeIf":         2,
+	"WrapUnlessCode":     1,
+	"WrapCodeCtx":        2,
+	"DeferWrap":          1,
+	"IsCode":             1,
+	"HasCode":            1,
+	"GetCoder":           0,
+	"NewCodef":           0,
+	"NewCodeT":           0,
+	"WrapCodeT":          1,
+	"NewBase":            0,
+	"NewBaseWithParams":  0,
+	"WrapBase":           1,
+	"NewSentinel":        0,
+	"LazyCoder":          0,
+	"Assert":             1,
+	"Assertf":            1,
+	"FromExec":           1,
+	"FromPanic":          1,
+	"WithTimeoutCode":    2,
+	"GoWrap":             1,
+	"MapStdError":        1,
+	"LifecycleOf":        0,
+}
+
+// codeListArgs maps the functions of the errors package that take a list of
+// codes to the index of their first code argument.
+var codeListArgs = map[string]int{
+	"HasAnyCode": 1,
+}
+
+// wrappers are the functions of the errors package that annotate an error
+// with a code. The other functions taking an error, such as Wrap, keep the
+// origin of the error.
+var wrappers = map[string]bool{
+	"WrapCode":           true,
+	"WrapCodeWithParams": true,
+	"WrapCodeIf":         true,
+	"WrapUnlessCode":     true,
+	"WrapCodeCtx":        true,
+	"WrapCodeT":          true,
+	"WrapBase":           true,
+	"FromExec":           true,
+	"Classify":           true,
+	"ClassifyContext":    true,
+	"ClassifyFS":         true,
+}
+
+var registry string
+
+// Analyzer checks the use of coded errors.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errcodelint",
+	Doc:      "check the use of coded errors: unknown codes, uncoded error returns and IsCode misuse",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&registry, "registry", "", "file listing the registered codes, one per line; # starts a comment")
+}
+
+// readRegistry returns the codes listed in the registry file at path.
+func readRegistry(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	codes := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			codes[line] = true
+		}
+	}
+
+	return codes, scanner.Err()
+}
+
+// origin describes where the current value of an error variable comes from.
+type origin int
+
+const (
+	local   origin = iota // created in the function
+	foreign               // returned by a call into another package
+	unknown               // a parameter or a call into this package
+)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var codes map[string]bool
+	if registry != "" {
+		var err error
+		if codes, err = readRegistry(registry); err != nil {
+			return nil, err
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var typ *ast.FuncType
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			typ, body = fn.Type, fn.Body
+		case *ast.FuncLit:
+			typ, body = fn.Type, fn.Body
+		}
+		if body != nil {
+			checkFunc(pass, codes, typ, body)
+		}
+	})
+
+	return nil, nil
+}
+
+// checkFunc checks the body of a single function. Nested function literals
+// are checked on their own.
+func checkFunc(pass *analysis.Pass, codes map[string]bool, typ *ast.FuncType, body *ast.BlockStmt) {
+	origins := map[types.Object]origin{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			for _, name := range field.Names {
+				if obj := pass.TypesInfo.Defs[name]; obj != nil && isError(obj.Type()) {
+					origins[obj] = unknown
+				}
+			}
+		}
+	}
+
+	deferred := deferredWraps(pass, body)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			track(pass, origins, n)
+		case *ast.CallExpr:
+			checkCall(pass, codes, origins, n)
+		case *ast.ReturnStmt:
+			checkReturn(pass, origins, deferred, n)
+		}
+
+		return true
+	})
+}
+
+// deferredWraps returns the error variables annotated with a code by a
+// deferred call to DeferWrap in body, such as err in
+//
+//     defer errors.DeferWrap(&err, "store.save")
+//
+// They are coded when the function returns, wherever they come from.
+func deferredWraps(pass *analysis.Pass, body *ast.BlockStmt) map[types.Object]bool {
+	wrapped := map[types.Object]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			if errorsFunc(pass, n.Call) != "DeferWrap" || len(n.Call.Args) == 0 {
+				return true
+			}
+			if addr, ok := n.Call.Args[0].(*ast.UnaryExpr); ok && addr.Op == token.AND {
+				if id, ok := addr.X.(*ast.Ident); ok {
+					wrapped[pass.TypesInfo.ObjectOf(id)] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	return wrapped
+}
+
+// track records the origin of the error variables assigned by assign.
+func track(pass *analysis.Pass, origins map[types.Object]origin, assign *ast.AssignStmt) {
+	o := local
+	if len(assign.Rhs) == 1 {
+		if call, ok := assign.Rhs[0].(*ast.CallExpr); ok {
+			o = callOrigin(pass, origins, call)
+		}
+	}
+
+	for _, lhs := range assign.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj := pass.TypesInfo.ObjectOf(id)
+		if obj != nil && isError(obj.Type()) {
+			origins[obj] = o
+		}
+	}
+}
+
+// callOrigin returns the origin of an error returned by call.
+func callOrigin(pass *analysis.Pass, origins map[types.Object]origin, call *ast.CallExpr) origin {
+	if isCheck(pass, call) {
+		return local
+	}
+
+	fn := callee(pass, call)
+	switch {
+	case fn == nil || fn.Pkg() == nil:
+		return unknown
+	case errorsPaths[fn.Pkg().Path()]:
+		if wrappers[fn.Name()] || len(call.Args) == 0 {
+			return local
+		}
+		if id, ok := call.Args[0].(*ast.Ident); ok {
+			if o, ok := origins[pass.TypesInfo.ObjectOf(id)]; ok {
+				return o
+			}
+		}
+		return local
+	case fn.Pkg() != pass.Pkg:
+		return foreign
+	}
+
+	return unknown
+}
+
+func checkCall(pass *analysis.Pass, codes map[string]bool, origins map[types.Object]origin, call *ast.CallExpr) {
+	// The code of Check is passed to the function it returns:
+	// errors.Check(v, err)("code").
+	if isCheck(pass, call) {
+		checkCode(pass, codes, call, 0)
+		return
+	}
+
+	name := errorsFunc(pass, call)
+	if i, ok := codeArgs[name]; ok {
+		checkCode(pass, codes, call, i)
+	}
+	if i, ok := codeListArgs[name]; ok {
+		for ; i < len(call.Args); i++ {
+			checkCode(pass, codes, call, i)
+		}
+	}
+
+	if name == "IsCode" && len(call.Args) > 0 {
+		if id, ok := call.Args[0].(*ast.Ident); ok {
+			if o, ok := origins[pass.TypesInfo.ObjectOf(id)]; ok && o != local {
+				pass.Reportf(call.Pos(), "IsCode only inspects the outermost error of %s, which may be wrapped; use HasCode to search the chain", id.Name)
+			}
+		}
+	}
+}
+
+// checkCode reports the i-th argument of call if it is a string literal code
+// that is not in the registry.
+func checkCode(pass *analysis.Pass, codes map[string]bool, call *ast.CallExpr, i int) {
+	if codes == nil || i >= len(call.Args) {
+		return
+	}
+
+	tv := pass.TypesInfo.Types[call.Args[i]]
+	if tv.Value != nil && tv.Value.Kind() == constant.String {
+		if code := constant.StringVal(tv.Value); !codes[code] {
+			pass.Reportf(call.Args[i].Pos(), "code %q is not in the registry", code)
+		}
+	}
+}
+
+func checkReturn(pass *analysis.Pass, origins map[types.Object]origin, deferred map[types.Object]bool, ret *ast.ReturnStmt) {
+	if len(ret.Results) == 0 {
+		return
+	}
+
+	id, ok := ret.Results[len(ret.Results)-1].(*ast.Ident)
+	if !ok {
+		return
+	}
+	if obj := pass.TypesInfo.ObjectOf(id); origins[obj] == foreign && !deferred[obj] {
+		pass.Reportf(id.Pos(), "error %s from another package is returned without a code; annotate it with WrapCode", id.Name)
+	}
+}
+
+// errorsFunc returns the name of the function of the errors package called
+// by call, or the empty string if call calls another function or a method.
+func errorsFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn := callee(pass, call)
+	if fn == nil || fn.Pkg() == nil || !errorsPaths[fn.Pkg().Path()] || isMethod(fn) {
+		return ""
+	}
+
+	return fn.Name()
+}
+
+// isCheck reports whether call calls the function returned by Check.
+func isCheck(pass *analysis.Pass, call *ast.CallExpr) bool {
+	inner, ok := ast.Unparen(call.Fun).(*ast.CallExpr)
+	return ok && errorsFunc(pass, inner) == "Check"
+}
+
+// callee returns the function or method called by call, or nil.
+// Explicitly instantiated generic functions, such as NewCodeT[P], return
+// their generic function.
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var id *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+
+	fn, _ := pass.TypesInfo.Uses[id].(*types.Func)
+	return fn
+}
+
+func isMethod(fn *types.Func) bool {
+	return fn.Type().(*types.Signature).Recv() != nil
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func isError(t types.Type) bool {
+	return types.Identical(t, errorType)
+}
@@ -0,0 +1,20 @@
+package errcodelint
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("registry", "testdata/codes.txt"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("registry", "")
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestCompareAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), CompareAnalyzer, "c")
+}
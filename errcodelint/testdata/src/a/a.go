@@ -0,0 +1,111 @@
+package a
+
+import (
+	"context"
+	"time"
+
+	"b"
+
+	"github.com/pkg/errors"
+)
+
+func registered() error {
+	return errors.NewCode("user.notfound")
+}
+
+func unregistered() error {
+	return errors.NewCode("user.missing") // want `code "user.missing" is not in the registry`
+}
+
+func uncoded() (string, error) {
+	s, err := b.Load()
+	if err != nil {
+		return "", err // want `error err from another package is returned without a code`
+	}
+
+	return s, nil
+}
+
+func coded() (string, error) {
+	s, err := b.Load()
+	if err != nil {
+		return "", errors.WrapCode(err, "user.notfound")
+	}
+
+	return s, nil
+}
+
+func rewrapped() error {
+	_, err := b.Load()
+	err = errors.WrapCode(err, "user.notfound")
+	return err
+}
+
+func deferred() (s string, err error) {
+	defer errors.DeferWrap(&err, "user.notfound")
+
+	s, err = b.Load()
+	if err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
+
+func annotated() error {
+	_, err := b.Load()
+	err = errors.Wrap(err, "load")
+	return err // want `error err from another package is returned without a code`
+}
+
+func local() error {
+	err := errors.NewCode("user.notfound")
+	if errors.IsCode(err, "user.notfound") {
+		return err
+	}
+
+	return nil
+}
+
+func param(err error) bool {
+	return errors.IsCode(err, "user.notfound") // want `IsCode only inspects the outermost error of err`
+}
+
+func search(err error) bool {
+	return errors.HasCode(err, "user.notfound")
+}
+
+type params struct{ ID string }
+
+func constructors(ctx context.Context, err error) {
+	_ = errors.NewCodef("user.missing", "load %s", "x")               // want `code "user.missing" is not in the registry`
+	_ = errors.NewCodeT("user.missing", params{})                     // want `code "user.missing" is not in the registry`
+	_ = errors.NewCodeT[params]("user.missing", params{})             // want `code "user.missing" is not in the registry`
+	_ = errors.WrapCodeT(err, "user.missing", params{})               // want `code "user.missing" is not in the registry`
+	_ = errors.NewBase("user.missing")                                // want `code "user.missing" is not in the registry`
+	_ = errors.WrapBase(err, "user.missing")                          // want `code "user.missing" is not in the registry`
+	_ = errors.NewSentinel("user.missing")                            // want `code "user.missing" is not in the registry`
+	_ = errors.Assert(false, "user.missing")                          // want `code "user.missing" is not in the registry`
+	_ = errors.Assertf(false, "user.missing", "%d", 1)                // want `code "user.missing" is not in the registry`
+	_ = errors.FromExec(err, "user.missing")                          // want `code "user.missing" is not in the registry`
+	_ = errors.FromPanic(nil, "user.missing")                         // want `code "user.missing" is not in the registry`
+	_ = errors.WithTimeoutCode(ctx, time.Second, "user.missing", nil) // want `code "user.missing" is not in the registry`
+	_ = errors.HasAnyCode(err, "user.notfound", "user.missing")       // want `code "user.missing" is not in the registry`
+	_, _ = errors.Check(b.Load())("user.missing")                     // want `code "user.missing" is not in the registry`
+	_ = errors.NewCodef("user.notfound", "load %s", "x")
+}
+
+func checked() (string, error) {
+	s, err := errors.Check(b.Load())("user.notfound")
+	if err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
+
+func executed() error {
+	_, err := b.Load()
+	err = errors.FromExec(err, "user.notfound")
+	return err
+}
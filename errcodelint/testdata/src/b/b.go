@@ -0,0 +1,3 @@
+package b
+
+func Load() (string, error) { return "", nil }
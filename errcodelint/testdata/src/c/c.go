@@ -0,0 +1,53 @@
+package c
+
+import (
+	"sentinel"
+
+	"github.com/pkg/errors"
+)
+
+var errLocal = errors.NewCode("user.local") // want errLocal:"coded"
+
+var errFormatted = errors.NewCodef("user.formatted", "formatted") // want errFormatted:"coded"
+
+var errSentinel = errors.NewSentinel("user.sentinel")
+
+func compare(err error) bool {
+	if err == sentinel.ErrNotFound { // want `coded errors are not comparable by identity`
+		return true
+	}
+	if errLocal != err { // want `coded errors are not comparable by identity`
+		return false
+	}
+
+	coded := errors.WrapCode(err, "user.wrapped")
+	if err == coded { // want `coded errors are not comparable by identity`
+		return true
+	}
+
+	if err == errFormatted { // want `coded errors are not comparable by identity`
+		return true
+	}
+	if err == errors.NewCodeT("user.typed", 1) { // want `coded errors are not comparable by identity`
+		return true
+	}
+	asserted := errors.Assert(false, "user.asserted")
+	if err == asserted { // want `coded errors are not comparable by identity`
+		return true
+	}
+	if errors.ClassifyFS(err) != err { // want `coded errors are not comparable by identity`
+		return false
+	}
+	if err == errSentinel {
+		return true
+	}
+
+	switch err {
+	case sentinel.ErrNotFound: // want `coded errors are not comparable by identity`
+		return true
+	case sentinel.ErrPlain:
+		return false
+	}
+
+	return err == sentinel.ErrPlain || err == nil
+}
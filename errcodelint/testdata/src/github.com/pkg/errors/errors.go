@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+type Base interface{ error }
+
+func New(message string) error                                    { return nil }
+func NewCode(code string, msgs ...string) error                   { return nil }
+func NewCodef(code, format string, args ...interface{}) error     { return nil }
+func NewCodeT[P any](code string, params P, msgs ...string) error { return nil }
+func Wrap(err error, message string) error                        { return nil }
+func WrapCode(err error, code string, msgs ...string) error       { return nil }
+func WrapCodeT[P any](err error, code string, params P, msgs ...string) error {
+	return nil
+}
+func WrapBase(err error, code string, msgs ...string) Base { return nil }
+func NewBase(code string, msgs ...string) Base             { return nil }
+func NewSentinel(code string) error                        { return nil }
+func Assert(cond bool, code string, msgs ...string) error  { return nil }
+func Assertf(cond bool, code, format string, args ...interface{}) error {
+	return nil
+}
+func FromExec(err error, code string, msgs ...string) error      { return nil }
+func FromPanic(v interface{}, code string, msgs ...string) error { return nil }
+func WithTimeoutCode(ctx context.Context, d time.Duration, code string, fn func(ctx context.Context) error, msgs ...string) error {
+	return nil
+}
+func Check[T any](v T, err error) func(code string, msgs ...string) (T, error) {
+	return nil
+}
+func ClassifyFS(err error) error                         { return nil }
+func IsCode(err error, code string) bool                 { return false }
+func HasCode(err error, code string) bool                { return false }
+func HasAnyCode(err error, codes ...string) bool         { return false }
+func DeferWrap(errp *error, code string, msgs ...string) {}
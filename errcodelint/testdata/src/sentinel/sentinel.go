@@ -0,0 +1,7 @@
+package sentinel
+
+import "github.com/pkg/errors"
+
+var ErrNotFound = errors.NewCode("user.notfound")
+
+var ErrPlain = errors.New("plain")
@@ -0,0 +1,121 @@
+// +build go1.20
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorfWrapping(t *testing.T) {
+	inner := NewCode("test.errorf.inner", "inner")
+
+	single := Errorf("read %s: %w", "config", io.EOF)
+	multi := Errorf("sync: %w, %w", inner, io.ErrUnexpectedEOF)
+
+	tests := []struct {
+		err     error
+		want    string
+		targets []error
+	}{
+		{single, "read config: EOF", []error{io.EOF}},
+		{multi, "sync: test.errorf.inner - inner, unexpected EOF", []error{inner, io.ErrUnexpectedEOF}},
+		{Errorf("no wrapping: %v", io.EOF), "no wrapping: EOF", nil},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Errorf: got %q, want %q", got, tt.want)
+		}
+		for _, target := range tt.targets {
+			if !stderrors.Is(tt.err, target) {
+				t.Errorf("Errorf(%q): does not wrap %v", tt.want, target)
+			}
+		}
+	}
+
+	if got := stderrors.Unwrap(single); got != io.EOF {
+		t.Errorf("Unwrap: got %v, want EOF", got)
+	}
+	if !HasCode(multi, "test.errorf.inner") {
+		t.Errorf("HasCode: multiple %%w causes are not searched")
+	}
+
+	got := fmt.Sprintf("%+v", multi)
+	for _, want := range []string{"test.errorf.inner - inner\n", "\nunexpected EOF\nsync: test.errorf.inner - inner, unexpected EOF\n", "errorf_test.go:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%%+v: missing %q in %q", want, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%q", single), `"read config: EOF"`; got != want {
+		t.Errorf("%%q: got %s, want %s", got, want)
+	}
+}
+
+func TestNewCodef(t *testing.T) {
+	inner := NewCode("test.errorf.inner", "inner")
+
+	tests := []struct {
+		err   error
+		want  string
+		codes []string
+	}{
+		{NewCodef("test.errorf", "user %d", 7), "test.errorf - user 7", []string{"test.errorf"}},
+		{NewCodef("test.errorf", "load %s: %w", "config", io.EOF), "test.errorf - load config: EOF", []string{"test.errorf"}},
+		{NewCodef("test.errorf", "load: %w", inner), "test.errorf - load: test.errorf.inner - inner", []string{"test.errorf", "test.errorf.inner"}},
+		{NewCodef("test.errorf", "sync: %w; %w", io.EOF, inner), "test.errorf - sync: EOF; test.errorf.inner - inner", []string{"test.errorf", "test.errorf.inner"}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("NewCodef: got %q, want %q", got, tt.want)
+		}
+		for _, code := range tt.codes {
+			if !HasCode(tt.err, code) {
+				t.Errorf("NewCodef(%q): HasCode(%q) is false", tt.want, code)
+			}
+		}
+		if got := Message(tt.err); !strings.HasPrefix(tt.want, "test.errorf - "+got) {
+			t.Errorf("Message: got %q", got)
+		}
+	}
+
+	multi := tests[3].err
+	if !stderrors.Is(multi, io.EOF) || !stderrors.Is(multi, inner) {
+		t.Errorf("NewCodef: multiple %%w causes are not wrapped")
+	}
+	if got := multi.(*Error).Cause(); len(got.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Errorf("NewCodef: got cause %v, want two errors", got)
+	}
+	if RootCause(tests[1].err) != io.EOF {
+		t.Errorf("RootCause: got %v, want EOF", RootCause(tests[1].err))
+	}
+
+	got := fmt.Sprintf("%+v", tests[2].err)
+	if !strings.HasPrefix(got, "test.errorf.inner - inner\n") || !strings.Contains(got, "\ntest.errorf - load: test.errorf.inner - inner\n") {
+		t.Errorf("%%+v: got %q", got)
+	}
+}
+
+func TestNewCodefWire(t *testing.T) {
+	err := NewCodef("test.errorf", "load: %w", WrapCode(io.EOF, "test.errorf.inner"))
+
+	data, jerr := ToJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	decoded, derr := FromJSON(data)
+	if derr != nil {
+		t.Fatal(derr)
+	}
+
+	if got, want := decoded.Error(), err.Error(); got != want {
+		t.Errorf("FromJSON(ToJSON()): got %q, want %q", got, want)
+	}
+	if !HasCode(decoded, "test.errorf.inner") {
+		t.Errorf("FromJSON(ToJSON()): lost the code of the cause: %v", decoded)
+	}
+}
@@ -95,6 +95,8 @@ package errors
 import (
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
 )
 
 // New returns an error with the supplied message.
@@ -108,14 +110,89 @@ func New(message string) error {
 
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
+// As with fmt.Errorf, the errors of %w verbs are wrapped: the returned
+// error implements Unwrap() error for a single %w verb, and Unwrap() []error
+// for several.
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	return &fundamental{
-		msg:   fmt.Sprintf(format, args...),
+	msg, wrapped := errorf(format, args)
+	switch len(wrapped) {
+	case 0:
+		return &fundamental{
+			msg:   msg,
+			stack: callers(),
+		}
+	case 1:
+		return &wrapError{
+			msg:   msg,
+			err:   wrapped[0],
+			stack: callers(),
+		}
+	}
+
+	return &wrapErrors{
+		msg:   msg,
+		errs:  wrapped,
 		stack: callers(),
 	}
 }
 
+// errorf formats according to a format specifier as fmt.Errorf does, and
+// returns the formatted message and the errors of its %w verbs.
+func errorf(format string, args []interface{}) (string, []error) {
+	err := fmt.Errorf(format, args...)
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return err.Error(), e.Unwrap()
+	case interface{ Unwrap() error }:
+		if wrapped := e.Unwrap(); wrapped != nil {
+			return err.Error(), []error{wrapped}
+		}
+	}
+
+	return err.Error(), nil
+}
+
+// bareMessage formats according to a format specifier as errorf does, but
+// without the text of the wrapped errors and the separators left around it.
+func bareMessage(format string, args []interface{}, wrapped []error) string {
+	bare := make([]interface{}, len(args))
+	for i, arg := range args {
+		bare[i] = arg
+		if err, ok := arg.(error); ok && isOneOf(err, wrapped) {
+			bare[i] = omittedError{}
+		}
+	}
+
+	return trimSeparators(fmt.Errorf(format, bare...).Error())
+}
+
+// isOneOf reports whether err is one of errs. Errors of non comparable
+// types are never found.
+func isOneOf(err error, errs []error) bool {
+	if !reflect.TypeOf(err).Comparable() {
+		return false
+	}
+	for _, e := range errs {
+		if e == err {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimSeparators trims the spaces and colons around msg, such as those left
+// by a removed "%w".
+func trimSeparators(msg string) string {
+	return strings.Trim(msg, " :")
+}
+
+// omittedError stands for a wrapped error left out of a message.
+type omittedError struct{}
+
+func (omittedError) Error() string { return "" }
+
 // fundamental is an error that has a message and a stack, but no caller.
 type fundamental struct {
 	msg string
@@ -140,6 +217,61 @@ func (f *fundamental) Format(s fmt.State, verb rune) {
 	}
 }
 
+// wrapError is an error formatted by Errorf wrapping the error of a single
+// %w verb.
+type wrapError struct {
+	msg string
+	err error
+	*stack
+}
+
+func (w *wrapError) Error() string { return w.msg }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *wrapError) Unwrap() error { return w.err }
+
+func (w *wrapError) Format(s fmt.State, verb rune) {
+	formatWrapped(s, verb, w.msg, []error{w.err}, w.stack)
+}
+
+// wrapErrors is an error formatted by Errorf wrapping the errors of several
+// %w verbs.
+type wrapErrors struct {
+	msg  string
+	errs []error
+	*stack
+}
+
+func (w *wrapErrors) Error() string { return w.msg }
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains.
+func (w *wrapErrors) Unwrap() []error { return w.errs }
+
+func (w *wrapErrors) Format(s fmt.State, verb rune) {
+	formatWrapped(s, verb, w.msg, w.errs, w.stack)
+}
+
+// formatWrapped formats an error returned by Errorf. With %+v the wrapped
+// errors are written first, as Wrap does for its cause.
+func formatWrapped(s fmt.State, verb rune, msg string, errs []error, st *stack) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for _, err := range errs {
+				fmt.Fprintf(s, "%+v\n", err)
+			}
+			io.WriteString(s, msg)
+			st.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, msg)
+	case 'q':
+		fmt.Fprintf(s, "%q", msg)
+	}
+}
+
 // WithStack annotates err with a stack trace at the point WithStack was called.
 // If err is nil, WithStack returns nil.
 func WithStack(err error) error {
@@ -241,7 +373,7 @@ type withMessage struct {
 	msg   string
 }
 
-func (w *withMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+func (w *withMessage) Error() string { return chainError(w) }
 func (w *withMessage) Cause() error  { return w.cause }
 
 // Unwrap provides compatibility for Go 1.13 error chains.
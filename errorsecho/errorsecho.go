@@ -0,0 +1,39 @@
+// Package errorsecho renders errors of package errors as Echo responses,
+// with the HTTP status and JSON body of errors.WriteHTTP.
+package errorsecho
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/pkg/errors"
+)
+
+// Abort writes err to c as errors.WriteHTTPLocalized does. It returns nil,
+// so that handlers can end with return errorsecho.Abort(c, err).
+func Abort(c echo.Context, err error) error {
+	errors.WriteHTTPLocalized(c.Response(), c.Request(), err)
+	return nil
+}
+
+// ErrorHandler returns an echo.HTTPErrorHandler that writes the errors
+// returned by handlers with Abort:
+//
+//     e := echo.New()
+//     e.HTTPErrorHandler = errorsecho.ErrorHandler()
+//
+// Echo's own *echo.HTTPError errors, such as those for unknown routes, are
+// written by Echo's default handler unless their chain has a code.
+func ErrorHandler() echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		if he, ok := err.(*echo.HTTPError); ok && errors.FirstCode(err) == "" {
+			c.Echo().DefaultHTTPErrorHandler(he, c)
+			return
+		}
+
+		Abort(c, err)
+	}
+}
@@ -0,0 +1,51 @@
+package errorsecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/pkg/errors"
+)
+
+type coder struct{}
+
+func (coder) Code() string                   { return "errorsecho.test" }
+func (coder) StatusCode() int                { return http.StatusConflict }
+func (coder) Message() string                { return "conflict" }
+func (coder) Params() map[string]interface{} { return nil }
+func (coder) FullMessage() string            { return "conflict" }
+func (coder) Reference() string              { return "" }
+
+func TestErrorHandler(t *testing.T) {
+	errors.Register(coder{})
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler()
+	e.GET("/error", func(c echo.Context) error {
+		return errors.Wrap(errors.NewCode("errorsecho.test"), "update")
+	})
+	e.GET("/abort", func(c echo.Context) error {
+		return Abort(c, errors.New("secret"))
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/error", 409, `{"code":"errorsecho.test","message":"conflict"}` + "\n"},
+		{"/abort", 500, `{"message":"Internal Server Error"}` + "\n"},
+		{"/missing", 404, `{"message":"Not Found"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("%s: got %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package errorsfiber renders errors of package errors as Fiber responses,
+// with the HTTP status and JSON body of errors.WriteHTTP.
+package errorsfiber
+
+import (
+	stderrors "errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkg/errors"
+)
+
+// Abort writes err to c with the status, headers and body returned by
+// errors.RenderHTTP. It returns the error of sending the response, so that
+// handlers can end with return errorsfiber.Abort(c, err).
+func Abort(c *fiber.Ctx, err error) error {
+	status, header, body := errors.RenderHTTP(err)
+	for k, v := range header {
+		for _, v := range v {
+			c.Set(k, v)
+		}
+	}
+
+	return c.Status(status).Send(body)
+}
+
+// ErrorHandler returns a fiber.ErrorHandler that writes the errors returned
+// by handlers with Abort:
+//
+//     app := fiber.New(fiber.Config{ErrorHandler: errorsfiber.ErrorHandler()})
+//
+// Fiber's own *fiber.Error errors, such as those for unknown routes, are
+// written by Fiber's default handler unless their chain has a code.
+func ErrorHandler() fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		var fe *fiber.Error
+		if stderrors.As(err, &fe) && errors.FirstCode(err) == "" {
+			return fiber.DefaultErrorHandler(c, err)
+		}
+
+		return Abort(c, err)
+	}
+}
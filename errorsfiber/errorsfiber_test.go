@@ -0,0 +1,59 @@
+package errorsfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkg/errors"
+)
+
+type coder struct{}
+
+func (coder) Code() string                   { return "errorsfiber.test" }
+func (coder) StatusCode() int                { return http.StatusServiceUnavailable }
+func (coder) Message() string                { return "unavailable" }
+func (coder) Params() map[string]interface{} { return nil }
+func (coder) FullMessage() string            { return "unavailable" }
+func (coder) Reference() string              { return "" }
+
+func TestErrorHandler(t *testing.T) {
+	errors.Register(coder{})
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Get("/error", func(c *fiber.Ctx) error {
+		return errors.WithRetryAfter(errors.NewCode("errorsfiber.test"), time.Second)
+	})
+	app.Get("/abort", func(c *fiber.Ctx) error {
+		return Abort(c, errors.New("secret"))
+	})
+
+	tests := []struct {
+		path       string
+		status     int
+		retryAfter string
+		body       string
+	}{
+		{"/error", 503, "1", `{"code":"errorsfiber.test","message":"unavailable"}`},
+		{"/abort", 500, "", `{"message":"Internal Server Error"}`},
+		{"/missing", 404, "", "Cannot GET /missing"},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status || string(body) != tt.body {
+			t.Errorf("%s: got %d %q, want %d %q", tt.path, resp.StatusCode, body, tt.status, tt.body)
+		}
+		if got := resp.Header.Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("%s: Retry-After: got %q, want %q", tt.path, got, tt.retryAfter)
+		}
+	}
+}
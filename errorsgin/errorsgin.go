@@ -0,0 +1,31 @@
+// Package errorsgin renders errors of package errors as Gin responses, with
+// the HTTP status and JSON body of errors.WriteHTTP.
+package errorsgin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/pkg/errors"
+)
+
+// Abort writes err to c as errors.WriteHTTPLocalized does and aborts the
+// remaining handlers of c.
+func Abort(c *gin.Context, err error) {
+	c.Abort()
+	errors.WriteHTTPLocalized(c.Writer, c.Request, err)
+}
+
+// ErrorHandler returns a middleware that writes the last error added to the
+// context with c.Error, unless a response has already been written:
+//
+//     r := gin.New()
+//     r.Use(errorsgin.ErrorHandler())
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if err := c.Errors.Last(); err != nil && !c.Writer.Written() {
+			Abort(c, err.Err)
+		}
+	}
+}
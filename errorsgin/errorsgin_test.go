@@ -0,0 +1,56 @@
+package errorsgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/pkg/errors"
+)
+
+type coder struct{}
+
+func (coder) Code() string                   { return "errorsgin.test" }
+func (coder) StatusCode() int                { return http.StatusNotFound }
+func (coder) Message() string                { return "not found" }
+func (coder) Params() map[string]interface{} { return nil }
+func (coder) FullMessage() string            { return "not found" }
+func (coder) Reference() string              { return "" }
+
+func TestErrorHandler(t *testing.T) {
+	errors.Register(coder{})
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/error", func(c *gin.Context) {
+		c.Error(errors.NewCode("errorsgin.test"))
+	})
+	r.GET("/abort", func(c *gin.Context) {
+		Abort(c, errors.New("secret"))
+	})
+	r.GET("/ok", func(c *gin.Context) {
+		c.Error(errors.New("logged"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/error", 404, `{"code":"errorsgin.test","message":"not found"}` + "\n"},
+		{"/abort", 500, `{"message":"Internal Server Error"}` + "\n"},
+		{"/ok", 200, "ok"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("%s: got %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package errorsgorm converts the errors of GORM operations into coded
+// errors of package errors, with the codes of package errorssql:
+//
+//     db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+//     db.Use(errorsgorm.Plugin{})
+//
+// With TranslateError set, GORM translates duplicate key and foreign key
+// violations of all its dialects; without it, violations are recognized
+// from the SQLSTATE of the driver error as errorssql.Convert does.
+package errorsgorm
+
+import (
+	stderrors "errors"
+
+	"gorm.io/gorm"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/errors/errorssql"
+)
+
+// Plugin is a gorm.Plugin that converts the error of each operation with
+// Convert, so that db.Error and the errors returned by the finisher methods
+// carry codes.
+type Plugin struct{}
+
+// Name returns the name of the plugin.
+func (Plugin) Name() string { return "errors" }
+
+// Initialize registers the conversion after each operation of db.
+func (Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().After("gorm:create").Register("errors:convert", convert),
+		cb.Query().After("gorm:query").Register("errors:convert", convert),
+		cb.Update().After("gorm:update").Register("errors:convert", convert),
+		cb.Delete().After("gorm:delete").Register("errors:convert", convert),
+		cb.Row().After("gorm:row").Register("errors:convert", convert),
+		cb.Raw().After("gorm:raw").Register("errors:convert", convert),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convert(db *gorm.DB) {
+	if db.Error != nil {
+		db.Error = Convert(db.Error)
+	}
+}
+
+// Convert annotates err with the errorssql code of its kind of database
+// error: gorm.ErrRecordNotFound is coded with errorssql.NotFoundCode,
+// gorm.ErrDuplicatedKey with errorssql.DuplicateCode and
+// gorm.ErrForeignKeyViolated with errorssql.ForeignKeyCode. Other errors are
+// converted by errorssql.Convert.
+// If err is nil, Convert returns nil.
+func Convert(err error) error {
+	if err == nil || errors.FirstCode(err) != "" {
+		return err
+	}
+
+	switch {
+	case stderrors.Is(err, gorm.ErrRecordNotFound):
+		return errors.WrapCode(err, errorssql.NotFoundCode)
+	case stderrors.Is(err, gorm.ErrDuplicatedKey):
+		return errors.WrapCode(err, errorssql.DuplicateCode)
+	case stderrors.Is(err, gorm.ErrForeignKeyViolated):
+		return errors.WrapCode(err, errorssql.ForeignKeyCode)
+	}
+
+	return errorssql.Convert(err)
+}
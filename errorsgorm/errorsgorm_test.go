@@ -0,0 +1,57 @@
+package errorsgorm
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/errors/errorssql"
+)
+
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{gorm.ErrRecordNotFound, errorssql.NotFoundCode},
+		{fmt.Errorf("find: %w", gorm.ErrDuplicatedKey), errorssql.DuplicateCode},
+		{gorm.ErrForeignKeyViolated, errorssql.ForeignKeyCode},
+		{stateError("23505"), errorssql.DuplicateCode},
+		{io.EOF, ""},
+	}
+
+	for i, tt := range tests {
+		if got := errors.FirstCode(Convert(tt.err)); got != tt.code {
+			t.Errorf("test %d: Convert(%v): got code %q, want %q", i+1, tt.err, got, tt.code)
+		}
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Callback().Query().Replace("gorm:query", func(db *gorm.DB) {
+		db.AddError(gorm.ErrRecordNotFound)
+	})
+
+	var v struct{ ID int }
+	err = db.Table("users").First(&v).Error
+	if got := errors.FirstCode(err); got != errorssql.NotFoundCode {
+		t.Errorf("First: got code %q (%v), want %q", got, err, errorssql.NotFoundCode)
+	}
+}
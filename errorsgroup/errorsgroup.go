@@ -0,0 +1,111 @@
+// Package errorsgroup wraps golang.org/x/sync/errgroup so that the error of
+// each task is annotated with the task name, and the errors of all failed
+// tasks can be inspected after Wait.
+package errorsgroup
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pkg/errors"
+)
+
+// TaskCode is the code of the errors returned by failed tasks. The name of
+// the task is recorded in the "task" param.
+const TaskCode = "errorsgroup.task"
+
+// A Group is a collection of named goroutines working on subtasks of a
+// common task. The zero value is not usable; use New or WithContext.
+type Group struct {
+	g *errgroup.Group
+
+	mu    sync.Mutex
+	names []string
+	errs  []error // by task index, as names
+}
+
+// New returns a Group with no context.
+func New() *Group {
+	return &Group{g: &errgroup.Group{}}
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx,
+// as errgroup.WithContext does. The derived Context is canceled the first
+// time a task fails or Wait returns.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	return &Group{g: g}, ctx
+}
+
+// SetLimit limits the number of active goroutines in the group to at most n.
+// A negative value indicates no limit.
+func (g *Group) SetLimit(n int) {
+	g.g.SetLimit(n)
+}
+
+// Go calls fn in a new goroutine as the task name. An error returned by fn
+// is annotated with TaskCode and the task name. Several tasks may have the
+// same name.
+func (g *Group) Go(name string, fn func() error) {
+	g.mu.Lock()
+	index := len(g.names)
+	g.names = append(g.names, name)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+
+	g.g.Go(func() error {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		err = errors.WrapCodeWithParams(err, TaskCode, map[string]interface{}{"task": name}, "task "+name+" failed")
+
+		g.mu.Lock()
+		g.errs[index] = err
+		g.mu.Unlock()
+
+		return err
+	})
+}
+
+// Wait blocks until all tasks have returned. It returns nil if no task
+// failed, and otherwise an errors.Aggregate of the errors of the failed
+// tasks in the order the tasks were started.
+func (g *Group) Wait() error {
+	g.g.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return errors.NewAggregate(g.errs)
+}
+
+// Err returns the error of the task name, or nil if it did not fail. If
+// several tasks have that name, the error of the first one started that
+// failed is returned.
+func (g *Group) Err(name string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, n := range g.names {
+		if n == name && g.errs[i] != nil {
+			return g.errs[i]
+		}
+	}
+
+	return nil
+}
+
+// Task returns the name of the task that failed with err, or the empty
+// string if err was not returned by a task of a Group.
+func Task(err error) string {
+	task, _ := errors.Params(err)["task"].(string)
+	if errors.Code(err) != TaskCode {
+		return ""
+	}
+
+	return task
+}
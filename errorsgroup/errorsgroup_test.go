@@ -0,0 +1,72 @@
+package errorsgroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestGroup(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+
+	g.Go("load", func() error { return errors.NewCode("test.load", "load failed") })
+	g.Go("ok", func() error { return nil })
+	g.Go("save", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	agg, ok := err.(errors.Aggregate)
+	if !ok {
+		t.Fatalf("Wait(): got %T, want errors.Aggregate", err)
+	}
+
+	errs := agg.Errors()
+	if len(errs) != 2 || Task(errs[0]) != "load" || Task(errs[1]) != "save" {
+		t.Fatalf("Wait(): got %v", errs)
+	}
+	if !errors.HasCode(g.Err("load"), "test.load") {
+		t.Errorf("Err(load): got %v, want cause test.load", g.Err("load"))
+	}
+	if g.Err("ok") != nil {
+		t.Errorf("Err(ok): got %v, want nil", g.Err("ok"))
+	}
+	if got, want := errs[0].Error(), "errorsgroup.task - task load failed: test.load - load failed"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+}
+
+func TestGroupSuccess(t *testing.T) {
+	g := New()
+	g.SetLimit(1)
+	for _, name := range []string{"a", "b"} {
+		g.Go(name, func() error { return nil })
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait(): got %v, want nil", err)
+	}
+	if got := Task(errors.New("plain")); got != "" {
+		t.Errorf("Task(plain): got %q, want empty", got)
+	}
+}
+
+func TestGroupDuplicateNames(t *testing.T) {
+	g := New()
+	g.Go("shard", func() error { return errors.NewCode("test.shard.a") })
+	g.Go("shard", func() error { return errors.NewCode("test.shard.b") })
+
+	agg, ok := g.Wait().(errors.Aggregate)
+	if !ok {
+		t.Fatalf("Wait(): got %T, want errors.Aggregate", g.Wait())
+	}
+	errs := agg.Errors()
+	if len(errs) != 2 || !errors.HasCode(errs[0], "test.shard.a") || !errors.HasCode(errs[1], "test.shard.b") {
+		t.Errorf("Wait(): got %v, want the errors of both tasks", errs)
+	}
+	if !errors.HasCode(g.Err("shard"), "test.shard.a") {
+		t.Errorf("Err(shard): got %v, want the error of the first task", g.Err("shard"))
+	}
+}
@@ -0,0 +1,88 @@
+// Package errorsredis classifies the errors of go-redis into coded errors
+// of package errors, so that cache fallbacks can branch on codes instead of
+// matching error strings:
+//
+//     v, err := rdb.Get(ctx, key).Result()
+//     switch errors.FirstCode(errorsredis.Classify(err)) {
+//     case errorsredis.MissCode:
+//             return load(ctx, key)
+//     case errorsredis.TimeoutCode, errorsredis.UnavailableCode:
+//             return loadWithoutCache(ctx, key)
+//     }
+//
+// The codes are not registered by this package; register Coders for them
+// to give them a status and message.
+package errorsredis
+
+import (
+	stderrors "errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pkg/errors"
+)
+
+// The codes of classified errors.
+const (
+	// MissCode is the code of redis.Nil, returned for missing keys.
+	MissCode = "cache.miss"
+
+	// TimeoutCode is the code of network and connection pool timeouts.
+	TimeoutCode = "cache.timeout"
+
+	// OOMCode is the code of OOM errors, returned for writes when Redis
+	// reached its memory limit.
+	OOMCode = "cache.oom"
+
+	// ReadOnlyCode is the code of READONLY errors, returned for writes to
+	// a replica.
+	ReadOnlyCode = "cache.readonly"
+
+	// UnavailableCode is the code of errors of servers that cannot serve
+	// requests: closed clients, network errors and LOADING, CLUSTERDOWN,
+	// MASTERDOWN and TRYAGAIN errors.
+	UnavailableCode = "cache.unavailable"
+)
+
+// Classify annotates err with the code of its kind of go-redis error.
+// Errors that already carry a code, and errors of other kinds such as
+// context errors and script errors, are returned unchanged.
+// If err is nil, Classify returns nil.
+func Classify(err error) error {
+	if err == nil || errors.FirstCode(err) != "" {
+		return err
+	}
+
+	if code := Code(err); code != "" {
+		return errors.WrapCode(err, code)
+	}
+
+	return err
+}
+
+// Code returns the code Classify annotates err with, or the empty string if
+// err is not a go-redis error known to Classify.
+func Code(err error) string {
+	var ne net.Error
+	switch {
+	case err == nil:
+		return ""
+	case stderrors.Is(err, redis.Nil):
+		return MissCode
+	case stderrors.Is(err, redis.ErrPoolTimeout):
+		return TimeoutCode
+	case stderrors.As(err, &ne) && ne.Timeout():
+		return TimeoutCode
+	case redis.IsOOMError(err):
+		return OOMCode
+	case redis.IsReadOnlyError(err):
+		return ReadOnlyCode
+	case stderrors.Is(err, redis.ErrClosed), ne != nil,
+		redis.IsLoadingError(err), redis.IsClusterDownError(err),
+		redis.IsMasterDownError(err), redis.IsTryAgainError(err):
+		return UnavailableCode
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package errorsredis
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pkg/errors"
+)
+
+// serve answers every command read from conn with reply.
+func serve(conn net.Conn, reply string) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		for i := 0; i < n; i++ {
+			size, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			l, _ := strconv.Atoi(strings.TrimSpace(size[1:]))
+			if _, err := io.CopyN(io.Discard, r, int64(l+2)); err != nil {
+				return
+			}
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// replyError returns the error of a GET answered with reply.
+func replyError(t *testing.T, reply string) error {
+	rdb := redis.NewClient(&redis.Options{
+		Protocol:        2,
+		DisableIdentity: true,
+		MaxRetries:      -1,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go serve(server, reply)
+			return client, nil
+		},
+	})
+	defer rdb.Close()
+
+	return rdb.Get(context.Background(), "key").Err()
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"nil reply", replyError(t, "$-1\r\n"), MissCode},
+		{"OOM", replyError(t, "-OOM command not allowed when used memory > 'maxmemory'.\r\n"), OOMCode},
+		{"READONLY", replyError(t, "-READONLY You can't write against a read only replica.\r\n"), ReadOnlyCode},
+		{"LOADING", replyError(t, "-LOADING Redis is loading the dataset in memory\r\n"), UnavailableCode},
+		{"script error", replyError(t, "-ERR Error running script\r\n"), ""},
+		{"timeout", &net.OpError{Op: "read", Err: timeoutError{}}, TimeoutCode},
+		{"pool timeout", redis.ErrPoolTimeout, TimeoutCode},
+		{"closed", redis.ErrClosed, UnavailableCode},
+		{"refused", &net.OpError{Op: "dial", Err: io.EOF}, UnavailableCode},
+		{"context", context.Canceled, ""},
+		{"coded", errors.NewCode("app.custom"), "app.custom"},
+	}
+
+	for _, tt := range tests {
+		got := Classify(tt.err)
+		if code := errors.FirstCode(got); code != tt.code {
+			t.Errorf("%s: Classify(%v): got code %q, want %q", tt.name, tt.err, code, tt.code)
+		}
+	}
+
+	if Classify(nil) != nil {
+		t.Errorf("Classify(nil): want nil")
+	}
+}
@@ -0,0 +1,113 @@
+// Package errorssql converts the errors of database/sql and of the packages
+// built on it, such as sqlx, into coded errors of package errors, so that
+// the data layer returns the same codes whatever the driver:
+//
+//     err := errorssql.Convert(db.GetContext(ctx, &user, query, id))
+//     if errors.HasCode(err, errorssql.NotFoundCode) {
+//             ...
+//     }
+//
+// The codes are not registered by this package; register Coders for them
+// to give them a status and message.
+package errorssql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The codes of converted errors.
+const (
+	// NotFoundCode is the code of queries that returned no rows.
+	NotFoundCode = "db.not_found"
+
+	// DuplicateCode is the code of unique constraint violations.
+	DuplicateCode = "db.duplicate"
+
+	// ForeignKeyCode is the code of foreign key constraint violations.
+	ForeignKeyCode = "db.foreign_key"
+
+	// ConstraintCode is the code of other integrity constraint violations,
+	// such as not null and check constraints.
+	ConstraintCode = "db.constraint"
+
+	// ConnectionCode is the code of errors of the database connection.
+	ConnectionCode = "db.connection"
+)
+
+// sqlStater is implemented by driver errors that carry an SQLSTATE code,
+// such as those of pgx and lib/pq.
+type sqlStater interface {
+	SQLState() string
+}
+
+// Convert annotates err with the code of its kind of database error:
+//
+//     sql.ErrNoRows                             NotFoundCode
+//     SQLSTATE 23505                            DuplicateCode
+//     SQLSTATE 23503                            ForeignKeyCode
+//     other SQLSTATE class 23                   ConstraintCode
+//     SQLSTATE class 08, sql.ErrConnDone,
+//     driver.ErrBadConn and net.Error           ConnectionCode
+//
+// SQLSTATE codes are read from driver errors with a SQLState() string
+// method. Errors that already carry a code, context errors and errors of
+// other kinds are returned unchanged. If err is nil, Convert returns nil.
+func Convert(err error) error {
+	if err == nil || errors.FirstCode(err) != "" {
+		return err
+	}
+
+	if code := codeOf(err); code != "" {
+		return errors.WrapCode(err, code)
+	}
+
+	return err
+}
+
+// Code returns the code Convert annotates err with, or the empty string if
+// err is not a database error known to Convert.
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return codeOf(err)
+}
+
+func codeOf(err error) string {
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return NotFoundCode
+	}
+
+	var s sqlStater
+	if stderrors.As(err, &s) {
+		state := s.SQLState()
+		switch {
+		case state == "23505":
+			return DuplicateCode
+		case state == "23503":
+			return ForeignKeyCode
+		case strings.HasPrefix(state, "23"):
+			return ConstraintCode
+		case strings.HasPrefix(state, "08"):
+			return ConnectionCode
+		}
+	}
+
+	var ne net.Error
+	if stderrors.Is(err, sql.ErrConnDone) || stderrors.Is(err, driver.ErrBadConn) || stderrors.As(err, &ne) {
+		return ConnectionCode
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+package errorssql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{sql.ErrNoRows, NotFoundCode},
+		{fmt.Errorf("get user: %w", sql.ErrNoRows), NotFoundCode},
+		{stateError("23505"), DuplicateCode},
+		{stateError("23503"), ForeignKeyCode},
+		{stateError("23502"), ConstraintCode},
+		{stateError("08006"), ConnectionCode},
+		{stateError("42P01"), ""},
+		{sql.ErrConnDone, ConnectionCode},
+		{driver.ErrBadConn, ConnectionCode},
+		{&net.OpError{Op: "dial", Err: io.EOF}, ConnectionCode},
+		{context.DeadlineExceeded, ""},
+		{io.EOF, ""},
+		{errors.NewCode("app.custom"), "app.custom"},
+	}
+
+	for i, tt := range tests {
+		got := Convert(tt.err)
+		if code := errors.FirstCode(got); code != tt.code {
+			t.Errorf("test %d: Convert(%v): got code %q, want %q", i+1, tt.err, code, tt.code)
+		}
+		if errors.Cause(got) != errors.Cause(tt.err) {
+			t.Errorf("test %d: Convert(%v): cause %v lost", i+1, tt.err, tt.err)
+		}
+	}
+
+	if Convert(nil) != nil {
+		t.Errorf("Convert(nil): want nil")
+	}
+}
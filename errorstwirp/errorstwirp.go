@@ -0,0 +1,113 @@
+// Package errorstwirp converts errors of package errors to and from Twirp
+// errors, mapping codes through the HTTP status codes of the registered
+// Coders:
+//
+//     func (s *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+//             u, err := s.store.Load(ctx, req.Id)
+//             if err != nil {
+//                     return nil, errorstwirp.ToTwirp(err)
+//             }
+//             return u, nil
+//     }
+package errorstwirp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/pkg/errors"
+)
+
+// CodeMeta is the metadata key of the code of a coded error, so that the
+// exact code survives the round trip through a Twirp error.
+const CodeMeta = "code"
+
+// twirpCodes maps HTTP status codes to Twirp error codes. It is the inverse
+// of twirp.ServerHTTPStatusFromErrorCode for the codes each status is the
+// most common for.
+var twirpCodes = map[int]twirp.ErrorCode{
+	http.StatusBadRequest:          twirp.InvalidArgument,
+	http.StatusUnauthorized:        twirp.Unauthenticated,
+	http.StatusForbidden:           twirp.PermissionDenied,
+	http.StatusNotFound:            twirp.NotFound,
+	http.StatusRequestTimeout:      twirp.DeadlineExceeded,
+	http.StatusConflict:            twirp.AlreadyExists,
+	http.StatusPreconditionFailed:  twirp.FailedPrecondition,
+	http.StatusTooManyRequests:     twirp.ResourceExhausted,
+	http.StatusInternalServerError: twirp.Internal,
+	http.StatusNotImplemented:      twirp.Unimplemented,
+	http.StatusServiceUnavailable:  twirp.Unavailable,
+}
+
+// ToTwirp converts err into a Twirp error.
+// The Twirp code is derived from the status code of the registered Coder of
+// the outermost coded error in err's chain, and the code itself is kept in
+// the CodeMeta metadata together with the params, formatted with fmt.Sprint.
+// Errors without a code become Internal errors, and coded errors whose
+// status has no Twirp code become Unknown errors.
+// If err is nil, ToTwirp returns nil.
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+
+	wc, ok := errors.AsError(err)
+	if !ok {
+		return twirp.NewError(twirp.Internal, err.Error())
+	}
+
+	code := twirp.Unknown
+	if coder := wc.Coder(); coder != nil {
+		if c, ok := twirpCodes[coder.StatusCode()]; ok {
+			code = c
+		}
+	}
+
+	terr := twirp.NewError(code, wc.Message())
+	for k, v := range wc.Params() {
+		terr = terr.WithMeta(k, fmt.Sprint(v))
+	}
+
+	return terr.WithMeta(CodeMeta, wc.Code())
+}
+
+// FromTwirp converts a Twirp error back into a coded error, with the other
+// metadata as params. The message of terr is kept, or else the message of
+// the registered Coder is used. The code is read from the CodeMeta
+// metadata; if there is none, it is the first registered code, in sorted
+// order, whose Coder has the HTTP status of the Twirp code. If no code is
+// found either way, terr is returned as an error without code, annotated
+// with a stack trace.
+// If terr is nil, FromTwirp returns nil.
+func FromTwirp(terr twirp.Error) error {
+	if terr == nil {
+		return nil
+	}
+
+	code := terr.Meta(CodeMeta)
+	if code == "" {
+		codes := errors.CodesWithStatus(twirp.ServerHTTPStatusFromErrorCode(terr.Code()))
+		if len(codes) == 0 {
+			return errors.WithStack(terr)
+		}
+		code = codes[0]
+	}
+
+	var params map[string]interface{}
+	for k, v := range terr.MetaMap() {
+		if k == CodeMeta {
+			continue
+		}
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		params[k] = v
+	}
+
+	if terr.Msg() == "" {
+		return errors.NewCodeWithParams(code, params)
+	}
+	return errors.NewCodeWithParams(code, params, terr.Msg())
+}
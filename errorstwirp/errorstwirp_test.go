@@ -0,0 +1,83 @@
+package errorstwirp
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/pkg/errors"
+)
+
+type testCoder struct {
+	code   string
+	status int
+}
+
+func (c testCoder) Code() string                   { return c.code }
+func (c testCoder) StatusCode() int                { return c.status }
+func (c testCoder) Message() string                { return c.code + " message" }
+func (c testCoder) Params() map[string]interface{} { return nil }
+func (c testCoder) FullMessage() string            { return c.Message() }
+func (c testCoder) Reference() string              { return "" }
+
+func init() {
+	errors.Register(testCoder{code: "test.twirp.notfound", status: http.StatusNotFound})
+	errors.Register(testCoder{code: "test.twirp.teapot", status: http.StatusTeapot})
+}
+
+func TestToTwirp(t *testing.T) {
+	if ToTwirp(nil) != nil {
+		t.Errorf("ToTwirp(nil): want nil")
+	}
+
+	tests := []struct {
+		err  error
+		code twirp.ErrorCode
+		msg  string
+		meta map[string]string
+	}{
+		{errors.New("boom"), twirp.Internal, "boom", nil},
+		{errors.NewCode("test.twirp.notfound"), twirp.NotFound, "test.twirp.notfound message", map[string]string{"code": "test.twirp.notfound"}},
+		{errors.NewCode("test.twirp.teapot", "short and stout"), twirp.Unknown, "short and stout", map[string]string{"code": "test.twirp.teapot"}},
+		{errors.Wrap(errors.NewCodeWithParams("test.twirp.notfound", map[string]interface{}{"id": 7}), "lookup"), twirp.NotFound, "test.twirp.notfound message", map[string]string{"code": "test.twirp.notfound", "id": "7"}},
+	}
+
+	for i, tt := range tests {
+		got := ToTwirp(tt.err)
+		meta := got.MetaMap()
+		if len(meta) == 0 {
+			meta = nil
+		}
+		if got.Code() != tt.code || got.Msg() != tt.msg || !reflect.DeepEqual(meta, tt.meta) {
+			t.Errorf("test %d: ToTwirp(%v): got %s %q %v, want %s %q %v", i+1, tt.err, got.Code(), got.Msg(), got.MetaMap(), tt.code, tt.msg, tt.meta)
+		}
+	}
+}
+
+func TestFromTwirp(t *testing.T) {
+	if FromTwirp(nil) != nil {
+		t.Errorf("FromTwirp(nil): want nil")
+	}
+
+	err := FromTwirp(ToTwirp(errors.NewCodeWithParams("test.twirp.teapot", map[string]interface{}{"id": 7}, "short and stout")))
+	if got, want := err.Error(), "test.twirp.teapot - short and stout"; got != want {
+		t.Errorf("FromTwirp: got %q, want %q", got, want)
+	}
+	if got := errors.Params(err); !reflect.DeepEqual(got, map[string]interface{}{"id": "7"}) {
+		t.Errorf("Params: got %v", got)
+	}
+
+	// Without the code metadata, the code is mapped through the status.
+	err = FromTwirp(twirp.NotFoundError(""))
+	if got, want := err.Error(), "test.twirp.notfound - test.twirp.notfound message"; got != want {
+		t.Errorf("FromTwirp(not_found): got %q, want %q", got, want)
+	}
+
+	terr := twirp.NewError(twirp.Unavailable, "try later")
+	err = FromTwirp(terr)
+	if errors.Code(err) != "" || errors.Cause(err) != terr {
+		t.Errorf("FromTwirp(unavailable): got %v, want the Twirp error without code", err)
+	}
+}
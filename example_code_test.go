@@ -0,0 +1,304 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+type exampleCoder struct {
+	code      string
+	status    int
+	message   string
+	reference string
+}
+
+func (c exampleCoder) Code() string                   { return c.code }
+func (c exampleCoder) StatusCode() int                { return c.status }
+func (c exampleCoder) Message() string                { return c.message }
+func (c exampleCoder) Params() map[string]interface{} { return nil }
+func (c exampleCoder) FullMessage() string            { return c.message }
+func (c exampleCoder) Reference() string              { return c.reference }
+
+func init() {
+	Register(exampleCoder{"example.user.notfound", http.StatusNotFound, "user not found", "https://example.com/errors#user-notfound"})
+	Register(exampleCoder{"example.db.query", http.StatusInternalServerError, "query failed", ""})
+}
+
+func ExampleNewCode() {
+	err := NewCode("example.user.notfound")
+	fmt.Println(err)
+
+	// Output: example.user.notfound - user not found
+}
+
+func ExampleNewCode_message() {
+	err := NewCode("example.user.notfound", "no user with this email")
+	fmt.Println(err)
+
+	// Output: example.user.notfound - no user with this email
+}
+
+func ExampleNewCodeWithParams() {
+	err := NewCodeWithParams("example.user.notfound", map[string]interface{}{"id": 42})
+	fmt.Println(Params(err)["id"])
+	fmt.Println(FullMessage(err))
+
+	// Output:
+	// 42
+	// {"params":{"id":42},"message":"user not found"}
+}
+
+func ExampleWrapCode() {
+	err := WrapCode(io.EOF, "example.db.query")
+	fmt.Println(err)
+	fmt.Println(Cause(err) == io.EOF)
+
+	// Output:
+	// example.db.query - query failed: EOF
+	// true
+}
+
+func ExampleWrapCodeWithParams() {
+	err := WrapCodeWithParams(io.EOF, "example.db.query", map[string]interface{}{"table": "users"})
+	fmt.Println(Params(err))
+
+	// Output: map[table:users]
+}
+
+func ExampleWrapCode_format() {
+	err := WrapCode(io.EOF, "example.db.query")
+	fmt.Printf("%s\n", err)
+	fmt.Printf("%v\n", err)
+	fmt.Printf("%q\n", err)
+
+	// Output:
+	// example.db.query - query failed: EOF
+	// example.db.query - query failed: EOF
+	// example.db.query - query failed: EOF
+}
+
+func ExampleWrapCode_printf() {
+	err := WrapCode(io.EOF, "example.db.query")
+	fmt.Printf("%+v", err)
+
+	// Example output:
+	// EOF
+	// example.db.query - query failed
+	// github.com/pkg/errors.ExampleWrapCode_printf
+	//         /home/dev/src/github.com/pkg/errors/example_code_test.go:85
+	// testing.runExample
+	//         /usr/local/go/src/testing/run_example.go:63
+	// ...
+}
+
+func ExampleWrapCodeIf() {
+	err := WrapCodeIf(false, io.EOF, "example.db.query")
+	fmt.Println(err)
+
+	// Output: EOF
+}
+
+func ExampleWrapUnlessCode() {
+	err := WrapUnlessCode(NewCode("example.db.query"), "example.db.query", "again")
+	fmt.Println(err)
+
+	// Output: example.db.query - query failed
+}
+
+func ExampleDeferWrap() {
+	load := func() (err error) {
+		defer DeferWrap(&err, "example.db.query")
+		return io.EOF
+	}
+
+	fmt.Println(load())
+
+	// Output: example.db.query - query failed: EOF
+}
+
+func ExampleFromCoder() {
+	coder := exampleCoder{"example.unregistered", http.StatusTeapot, "short and stout", ""}
+	err := FromCoder(coder)
+	fmt.Println(err)
+	fmt.Println(ParseCoder(err).StatusCode())
+
+	// Output:
+	// example.unregistered - short and stout
+	// 418
+}
+
+func ExampleGetCoder() {
+	coder := GetCoder("example.user.notfound")
+	fmt.Println(coder.StatusCode(), coder.Reference())
+
+	// Output: 404 https://example.com/errors#user-notfound
+}
+
+func ExampleParseCoder() {
+	coder := ParseCoder(NewCode("example.user.notfound"))
+	fmt.Println(coder.Code(), coder.StatusCode())
+
+	// Output: example.user.notfound 404
+}
+
+func ExampleIsCode() {
+	err := WrapCode(NewCode("example.user.notfound"), "example.db.query")
+	fmt.Println(IsCode(err, "example.db.query"))
+	fmt.Println(IsCode(err, "example.user.notfound"))
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleHasCode() {
+	err := WrapCode(NewCode("example.user.notfound"), "example.db.query")
+	fmt.Println(HasCode(err, "example.user.notfound"))
+
+	// Output: true
+}
+
+func ExampleHasAnyCode() {
+	err := NewCode("example.user.notfound")
+	fmt.Println(HasAnyCode(err, "example.db.query", "example.user.notfound"))
+
+	// Output: true
+}
+
+func ExampleHasCodePrefix() {
+	err := NewCode("example.user.notfound")
+	fmt.Println(HasCodePrefix(err, "example.user."))
+
+	// Output: true
+}
+
+func ExampleMatch() {
+	err := WrapCode(NewCode("example.user.notfound"), "example.db.query")
+	clientError := Match(err, func(code string, c Coder) bool {
+		return c != nil && c.StatusCode() < 500
+	})
+	fmt.Println(clientError)
+
+	// Output: true
+}
+
+func ExampleCodeAt() {
+	err := WrapCode(WrapCode(io.EOF, "example.user.notfound"), "example.db.query")
+	fmt.Println(FirstCode(err))
+	fmt.Println(CodeAt(err, 1))
+	fmt.Println(RootCode(err))
+
+	// Output:
+	// example.db.query
+	// example.user.notfound
+	// example.user.notfound
+}
+
+func ExampleCode() {
+	err := fmt.Errorf("handler: %w", NewCode("example.user.notfound"))
+	fmt.Println(Code(err))
+	fmt.Println(Message(err))
+
+	// Output:
+	// example.user.notfound
+	// user not found
+}
+
+func ExampleRootCause() {
+	err := WrapCode(fmt.Errorf("read: %w", io.EOF), "example.db.query")
+	fmt.Println(RootCause(err) == io.EOF)
+
+	// Output: true
+}
+
+func ExampleToJSON() {
+	err := WrapCodeWithParams(io.EOF, "example.db.query", map[string]interface{}{"table": "users"})
+
+	data, _ := ToJSON(err)
+	fmt.Println(string(data))
+
+	decoded, _ := FromJSON(data)
+	fmt.Println(decoded)
+	fmt.Println(HasCode(decoded, "example.db.query"))
+
+	// Output:
+	// {"schema_version":1,"chain":[{"code":"example.db.query","message":"query failed","params":{"table":"users"}},{"message":"EOF"}]}
+	// example.db.query - query failed: EOF
+	// true
+}
+
+func ExampleWriteHTTP() {
+	w := httptest.NewRecorder()
+	WriteHTTP(w, Wrap(NewCode("example.user.notfound"), "lookup"))
+
+	fmt.Println(w.Code)
+	fmt.Print(w.Body.String())
+
+	// Output:
+	// 404
+	// {"code":"example.user.notfound","message":"user not found","reference":"https://example.com/errors#user-notfound"}
+}
+
+func ExampleToGraphQL() {
+	gqlErr := ToGraphQL(NewCode("example.user.notfound"), "user")
+	fmt.Println(gqlErr.Message, gqlErr.Path, gqlErr.Extensions["code"])
+
+	// Output: user not found [user] example.user.notfound
+}
+
+func ExampleNewAggregate() {
+	err := NewAggregate([]error{NewCode("example.user.notfound"), io.EOF})
+	fmt.Println(len(err.(Aggregate).Errors()))
+	fmt.Println(HasCode(err.(Aggregate).Errors()[0], "example.user.notfound"))
+
+	// Output:
+	// 2
+	// true
+}
+
+func ExampleCollector() {
+	c := NewCollector()
+	c.Add(nil)
+	c.Add(NewCode("example.user.notfound"))
+	fmt.Println(c.Err())
+
+	// Output: example.user.notfound - user not found
+}
+
+func ExampleRetryAfter() {
+	err := WithRetryAfter(NewCode("example.db.query"), 2*time.Second)
+	after, ok := RetryAfter(err)
+	fmt.Println(after, ok)
+
+	// Output: 2s true
+}
+
+func ExampleReplaceCause() {
+	err := WrapCode(io.ErrUnexpectedEOF, "example.db.query")
+	fmt.Println(ReplaceCause(err, New("internal error")))
+	fmt.Println(StripCause(err))
+
+	// Output:
+	// example.db.query - query failed: internal error
+	// example.db.query - query failed
+}
+
+func ExamplePublic() {
+	err := WrapCodeWithParams(io.EOF, "example.db.query", map[string]interface{}{"table": "users"})
+	fmt.Println(Public(err))
+	fmt.Println(Params(Public(err)))
+
+	// Output:
+	// example.db.query - query failed
+	// map[]
+}
+
+func ExampleTruncate() {
+	err := WrapCode(Wrap(WrapCode(io.EOF, "example.db.query"), "load user"), "example.user.notfound")
+	fmt.Println(Truncate(err, 2))
+
+	// Output: example.user.notfound - user not found: load user: ...
+}
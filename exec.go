@@ -0,0 +1,78 @@
+// +build go1.13
+
+package errors
+
+import (
+	stderrors "errors"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExitCodeParam and StderrParam are the params set by FromExec to the exit
+// code of a command and the end of its standard error.
+const (
+	ExitCodeParam = "exit_code"
+	StderrParam   = "stderr"
+)
+
+// MaxExecStderr is the maximum number of bytes of standard error kept in
+// the StderrParam param by FromExec. The end of the output is kept, since
+// it usually holds the reason of the failure.
+var MaxExecStderr = 1024
+
+// FromExec annotates err, as returned by the Run, Output or CombinedOutput
+// methods of exec.Cmd, with the supplied code and message. If err's chain
+// contains an *exec.ExitError, its exit code is recorded in the
+// ExitCodeParam param, and the standard error captured by Output in the
+// StderrParam param:
+//
+//     out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+//     if err != nil {
+//             return errors.FromExec(err, "build.git")
+//     }
+//
+// The exit code is -1 if the command was terminated by a signal.
+// If err is nil, FromExec returns nil.
+func FromExec(err error, code string, msgs ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	var params map[string]interface{}
+	var ee *exec.ExitError
+	if stderrors.As(err, &ee) {
+		params = map[string]interface{}{ExitCodeParam: ee.ExitCode()}
+		if stderr := tailStderr(ee.Stderr); stderr != "" {
+			params[StderrParam] = stderr
+		}
+	}
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  params,
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// tailStderr returns the last MaxExecStderr bytes of stderr without
+// surrounding white space, marked with a leading "..." if cut.
+func tailStderr(stderr []byte) string {
+	s := strings.TrimSpace(string(stderr))
+	if MaxExecStderr <= 0 || len(s) <= MaxExecStderr {
+		return s
+	}
+
+	i := len(s) - MaxExecStderr
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+
+	return "..." + s[i:]
+}
@@ -0,0 +1,89 @@
+// +build go1.13
+
+package errors
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestExecHelper is run as a subprocess by TestFromExec.
+func TestExecHelper(t *testing.T) {
+	if os.Getenv("ERRORS_EXEC_HELPER") != "1" {
+		return
+	}
+	fmt.Fprintln(os.Stderr, strings.Repeat("x", 10)+"fatal: not a repository")
+	os.Exit(3)
+}
+
+func helperCommand() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExecHelper$")
+	cmd.Env = append(os.Environ(), "ERRORS_EXEC_HELPER=1")
+	return cmd
+}
+
+func TestFromExec(t *testing.T) {
+	if FromExec(nil, "test.exec") != nil {
+		t.Errorf("FromExec(nil): want nil")
+	}
+
+	_, runErr := helperCommand().Output()
+	err := FromExec(runErr, "test.exec.failed", "git failed")
+
+	if !HasCode(err, "test.exec.failed") {
+		t.Errorf("FromExec: want code test.exec.failed, got %v", err)
+	}
+	params := Params(err)
+	if got := params[ExitCodeParam]; got != 3 {
+		t.Errorf("exit code: got %v, want 3", got)
+	}
+	if got, want := params[StderrParam], "xxxxxxxxxxfatal: not a repository"; got != want {
+		t.Errorf("stderr: got %q, want %q", got, want)
+	}
+	if Cause(err) != runErr {
+		t.Errorf("cause: got %v, want %v", Cause(err), runErr)
+	}
+
+	defer func(max int) { MaxExecStderr = max }(MaxExecStderr)
+	MaxExecStderr = 10
+	err = FromExec(runErr, "test.exec.failed")
+	if got, want := Params(err)[StderrParam], "...repository"; got != want {
+		t.Errorf("truncated stderr: got %q, want %q", got, want)
+	}
+
+	// Errors other than *exec.ExitError carry no params.
+	_, runErr = exec.Command("errors-test-no-such-command").Output()
+	err = FromExec(runErr, "test.exec.missing")
+	if !HasCode(err, "test.exec.missing") || len(Params(err)) != 0 {
+		t.Errorf("missing command: got %v with params %v", err, Params(err))
+	}
+
+	// Run does not capture stderr.
+	err = FromExec(helperCommand().Run(), "test.exec.failed")
+	if _, ok := Params(err)[StderrParam]; ok {
+		t.Errorf("Run: want no stderr param, got %v", Params(err))
+	}
+}
+
+func TestTailStderr(t *testing.T) {
+	defer func(max int) { MaxExecStderr = max }(MaxExecStderr)
+	MaxExecStderr = 4
+
+	tests := []struct {
+		stderr, want string
+	}{
+		{"", ""},
+		{"  ok\n", "ok"},
+		{"abcdef", "...cdef"},
+		{"aé€", "...€"},
+	}
+
+	for _, tt := range tests {
+		if got := tailStderr([]byte(tt.stderr)); got != tt.want {
+			t.Errorf("tailStderr(%q): got %q, want %q", tt.stderr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+// Flag is a set of operational properties of an error code, so that
+// systems can branch on what an error is rather than on lists of codes.
+// The predefined flags occupy the low bits; applications define their own
+// flags starting at FlagUser:
+//
+//     const FlagPageOnCall = errors.FlagUser << iota
+type Flag uint64
+
+const (
+	// FlagRetryable marks errors whose operation may be retried.
+	FlagRetryable Flag = 1 << iota
+
+	// FlagUserVisible marks errors whose message may be shown to end users.
+	FlagUserVisible
+
+	// FlagAlertable marks errors that should raise an alert.
+	FlagAlertable
+
+	// FlagBillingImpacting marks errors that affect billing.
+	FlagBillingImpacting
+
+	// FlagPermanent marks errors that will fail again however often the
+	// operation is retried, such as malformed input.
+	FlagPermanent
+
+	// FlagUser is the first flag available to applications.
+	FlagUser Flag = 1 << 32
+)
+
+// Flagger is implemented by Coders that carry flags.
+type Flagger interface {
+	Flags() Flag
+}
+
+// Flags returns the flags of the Coder of the outermost coded error in
+// err's chain. If there is no coded error, or its Coder does not implement
+// Flagger, no flags are returned.
+func Flags(err error) Flag {
+	if wc := codeOf(err); wc != nil {
+		if flagger, ok := wc.Coder().(Flagger); ok {
+			return flagger.Flags()
+		}
+	}
+
+	return 0
+}
+
+// HasFlag reports whether err has all the flags in flag, as returned by Flags.
+func HasFlag(err error, flag Flag) bool {
+	return flag != 0 && Flags(err)&flag == flag
+}
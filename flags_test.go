@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+type flagCoder struct {
+	testCoder
+	flags Flag
+}
+
+func (c flagCoder) Flags() Flag { return c.flags }
+
+func TestHasFlag(t *testing.T) {
+	const flagCustom = FlagUser << 1
+
+	Register(flagCoder{testCoder{code: "test.flags.timeout"}, FlagRetryable | FlagAlertable | flagCustom})
+
+	tests := []struct {
+		err  error
+		flag Flag
+		want bool
+	}{
+		{nil, FlagRetryable, false},
+		{io.EOF, FlagRetryable, false},
+		{NewCode("test.flags.unregistered"), FlagRetryable, false},
+		{NewCode("test.flags.timeout"), FlagRetryable, true},
+		{Wrap(NewCode("test.flags.timeout"), "call"), FlagRetryable | FlagAlertable, true},
+		{NewCode("test.flags.timeout"), FlagRetryable | FlagUserVisible, false},
+		{NewCode("test.flags.timeout"), flagCustom, true},
+		{NewCode("test.flags.timeout"), 0, false},
+	}
+
+	for i, tt := range tests {
+		if got := HasFlag(tt.err, tt.flag); got != tt.want {
+			t.Errorf("test %d: HasFlag(%v, %b): got %v, want %v", i+1, tt.err, tt.flag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+)
+
+// FrameCacheSize is the number of resolved stack frames cached, so that
+// formatting the stack traces of errors created at the same call sites does
+// not resolve their frames again. The least recently used frames are
+// evicted first. Zero disables the cache.
+var FrameCacheSize = 4096
+
+// frameInfo is the resolved function name, file and line of a Frame.
+type frameInfo struct {
+	name string
+	file string
+	line int
+}
+
+type frameEntry struct {
+	pc   uintptr
+	info frameInfo
+}
+
+var frameCache = struct {
+	sync.Mutex
+	entries map[uintptr]*list.Element
+	lru     list.List
+}{entries: map[uintptr]*list.Element{}}
+
+// resolve returns the function name, file and line of f, from the frame
+// cache if possible.
+func (f Frame) resolve() frameInfo {
+	pc := f.pc()
+
+	frameCache.Lock()
+	if e, ok := frameCache.entries[pc]; ok {
+		frameCache.lru.MoveToFront(e)
+		info := e.Value.(*frameEntry).info
+		frameCache.Unlock()
+		return info
+	}
+	frameCache.Unlock()
+
+	info := frameInfo{name: "unknown", file: "unknown"}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		info.name = fn.Name()
+		info.file, info.line = fn.FileLine(pc)
+	}
+
+	frameCache.Lock()
+	defer frameCache.Unlock()
+
+	if FrameCacheSize <= 0 {
+		return info
+	}
+	if _, ok := frameCache.entries[pc]; !ok {
+		frameCache.entries[pc] = frameCache.lru.PushFront(&frameEntry{pc: pc, info: info})
+	}
+	for frameCache.lru.Len() > FrameCacheSize {
+		oldest := frameCache.lru.Back()
+		frameCache.lru.Remove(oldest)
+		delete(frameCache.entries, oldest.Value.(*frameEntry).pc)
+	}
+
+	return info
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"container/list"
+	"fmt"
+	"testing"
+)
+
+func TestFrameCache(t *testing.T) {
+	saved := FrameCacheSize
+	defer func() { FrameCacheSize = saved }()
+
+	FrameCacheSize = 1
+	frameCache.Lock()
+	frameCache.entries = map[uintptr]*list.Element{}
+	frameCache.lru.Init()
+	frameCache.Unlock()
+
+	st := callers().StackTrace()
+	if len(st) < 2 {
+		t.Fatalf("stack of %d frames, want at least 2", len(st))
+	}
+
+	want := make([]string, 2)
+	for i := range want {
+		want[i] = fmt.Sprintf("%+v", st[i])
+	}
+
+	for i := range want {
+		if got := fmt.Sprintf("%+v", st[i]); got != want[i] {
+			t.Errorf("frame %d: got %q, want %q", i, got, want[i])
+		}
+	}
+
+	frameCache.Lock()
+	n := frameCache.lru.Len()
+	_, newest := frameCache.entries[st[1].pc()]
+	_, evicted := frameCache.entries[st[0].pc()]
+	frameCache.Unlock()
+
+	if n > FrameCacheSize {
+		t.Errorf("cache holds %d frames, more than %d", n, FrameCacheSize)
+	}
+	if !newest || evicted {
+		t.Errorf("cache holds newest frame %v and oldest frame %v, want true and false", newest, evicted)
+	}
+}
+
+func BenchmarkFrameFormatting(b *testing.B) {
+	st := callers().StackTrace()
+	for i := 0; i < b.N; i++ {
+		GlobalE = fmt.Sprintf("%+v", st)
+	}
+}
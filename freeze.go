@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// frozenCodes is the registry compiled by FreezeRegistry: the registered
+// codes, sorted, and their Coders at the same indexes, and the Coders of
+// the numeric codes. It is never modified once compiled.
+type frozenCodes struct {
+	codes  []string
+	coders []Coder
+	nums   map[int]Coder
+}
+
+// frozen holds the *frozenCodes of a frozen registry.
+var frozen atomic.Value
+
+// FreezeRegistry makes the registry immutable. The registered codes are
+// compiled into tables that GetCoder and GetCoderByNum search without
+// locking, for maximum lookup throughput in programs that register all codes
+// during initialization:
+//
+//     func main() {
+//             errors.FreezeRegistry()
+//             ...
+//     }
+//
+// Once the registry is frozen, Register and MustRegister panic and Reload
+// returns an error. Calling FreezeRegistry again has no effect.
+func FreezeRegistry() {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	if frozenRegistry() != nil {
+		return
+	}
+
+	f := &frozenCodes{
+		codes:  make([]string, 0, len(codes)),
+		coders: make([]Coder, 0, len(codes)),
+		nums:   make(map[int]Coder, len(numCodes)),
+	}
+	for code := range codes {
+		f.codes = append(f.codes, code)
+	}
+	sort.Strings(f.codes)
+	for _, code := range f.codes {
+		f.coders = append(f.coders, codes[code])
+	}
+	for n, code := range numCodes {
+		f.nums[n] = codes[code]
+	}
+
+	frozen.Store(f)
+}
+
+// frozenRegistry returns the frozen registry, or nil if the registry is not
+// frozen.
+func frozenRegistry() *frozenCodes {
+	f, _ := frozen.Load().(*frozenCodes)
+	return f
+}
+
+// lookup returns the Coder of code.
+func (f *frozenCodes) lookup(code string) Coder {
+	if i := sort.SearchStrings(f.codes, code); i < len(f.codes) && f.codes[i] == code {
+		return f.coders[i]
+	}
+
+	return nil
+}
+
+// lookupNum returns the Coder of the numeric code n.
+func (f *frozenCodes) lookupNum(n int) Coder {
+	return f.nums[n]
+}
@@ -0,0 +1,78 @@
+package errors
+
+import "testing"
+
+// unfreeze undoes FreezeRegistry, so that tests can register codes again.
+func unfreeze() {
+	frozen.Store((*frozenCodes)(nil))
+}
+
+func TestFreezeRegistry(t *testing.T) {
+	Register(testCoder{code: "test.frozen.a", message: "a"})
+	Register(testCoder{code: "test.frozen.b", message: "b"})
+	Register(numCoder{testCoder{code: "test.frozen.num", message: "num"}, 91901})
+
+	FreezeRegistry()
+	defer unfreeze()
+	FreezeRegistry()
+
+	for _, code := range []string{"test.frozen.a", "test.frozen.b"} {
+		if c := GetCoder(code); c == nil || c.Code() != code {
+			t.Errorf("GetCoder(%q): got %v", code, c)
+		}
+	}
+	if c := GetCoder("test.frozen.missing"); c != nil {
+		t.Errorf("GetCoder(missing): got %v, want nil", c)
+	}
+	if c := GetCoderByNum(91901); c == nil || c.Code() != "test.frozen.num" {
+		t.Errorf("GetCoderByNum(91901): got %v", c)
+	}
+	if c := GetCoderByNum(91902); c != nil {
+		t.Errorf("GetCoderByNum(missing): got %v, want nil", c)
+	}
+	if got := Message(NewCode("test.frozen.b")); got != "b" {
+		t.Errorf("Message: got %q, want %q", got, "b")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Register after FreezeRegistry: want panic")
+			}
+		}()
+		Register(testCoder{code: "test.frozen.c"})
+	}()
+
+	source := sourceFunc(func() ([]Coder, error) {
+		return []Coder{testCoder{code: "test.frozen.a", message: "reloaded"}}, nil
+	})
+	if err := Reload(source); err == nil {
+		t.Errorf("Reload after FreezeRegistry: want error")
+	}
+	if got := GetCoder("test.frozen.a").Message(); got != "a" {
+		t.Errorf("Message after Reload: got %q, want %q", got, "a")
+	}
+}
+
+func BenchmarkGetCoder(b *testing.B) {
+	Register(testCoder{code: "bench.get"})
+
+	b.Run("locked", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				GlobalE = GetCoder("bench.get")
+			}
+		})
+	})
+
+	FreezeRegistry()
+	defer unfreeze()
+
+	b.Run("frozen", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				GlobalE = GetCoder("bench.get")
+			}
+		})
+	})
+}
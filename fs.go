@@ -0,0 +1,88 @@
+// +build go1.13
+
+package errors
+
+import (
+	stderrors "errors"
+	"os"
+)
+
+// The canonical codes used by ClassifyFS for file system errors.
+var (
+	NotExistCode   = "fs.not_exist"
+	ExistCode      = "fs.exist"
+	PermissionCode = "fs.permission"
+	NoSpaceCode    = "fs.no_space"
+	ReadOnlyFSCode = "fs.read_only"
+)
+
+// PathParam is the param set by ClassifyFS to the path of the file the
+// failed operation was applied to.
+const PathParam = "path"
+
+// ClassifyFS annotates file system errors in err's chain with the code of
+// their kind: NotExistCode, ExistCode, PermissionCode, NoSpaceCode for full
+// disks and exceeded quotas, or ReadOnlyFSCode. The path of the
+// *os.PathError or *os.LinkError in the chain is recorded in the
+// PathParam param:
+//
+//     f, err := os.Open(name)
+//     if err != nil {
+//             return errors.ClassifyFS(err)
+//     }
+//
+// Errors that already carry a code, or that are not file system errors,
+// are returned unchanged. If err is nil, ClassifyFS returns nil.
+func ClassifyFS(err error) error {
+	if err == nil || codeOf(err) != nil {
+		return err
+	}
+
+	code := ""
+	switch {
+	case stderrors.Is(err, os.ErrNotExist):
+		code = NotExistCode
+	case stderrors.Is(err, os.ErrExist):
+		code = ExistCode
+	case stderrors.Is(err, os.ErrPermission):
+		code = PermissionCode
+	case isNoSpace(err):
+		code = NoSpaceCode
+	case isReadOnlyFS(err):
+		code = ReadOnlyFSCode
+	default:
+		return err
+	}
+
+	var params map[string]interface{}
+	if path := fsPath(err); path != "" {
+		params = map[string]interface{}{PathParam: path}
+	}
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(nil),
+		source:  messageSourceOf(nil),
+		params:  params,
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// fsPath returns the path of the outermost *os.PathError or *os.LinkError
+// in err's chain, the source path for links.
+func fsPath(err error) string {
+	var pe *os.PathError
+	var le *os.LinkError
+	switch {
+	case stderrors.As(err, &pe):
+		return pe.Path
+	case stderrors.As(err, &le):
+		return le.Old
+	}
+
+	return ""
+}
@@ -0,0 +1,20 @@
+// +build go1.13,!plan9
+
+package errors
+
+import (
+	stderrors "errors"
+	"syscall"
+)
+
+// isNoSpace reports whether err's chain contains the errno of a full disk
+// or an exceeded quota.
+func isNoSpace(err error) bool {
+	return stderrors.Is(err, syscall.ENOSPC) || stderrors.Is(err, syscall.EDQUOT)
+}
+
+// isReadOnlyFS reports whether err's chain contains the errno of a write to
+// a read-only file system.
+func isReadOnlyFS(err error) bool {
+	return stderrors.Is(err, syscall.EROFS)
+}
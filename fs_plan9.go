@@ -0,0 +1,11 @@
+// +build go1.13,plan9
+
+package errors
+
+// isNoSpace reports whether err is caused by a full disk. Plan 9 has no
+// errno to identify it.
+func isNoSpace(err error) bool { return false }
+
+// isReadOnlyFS reports whether err is caused by a read-only file system.
+// Plan 9 has no errno to identify it.
+func isReadOnlyFS(err error) bool { return false }
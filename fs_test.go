@@ -0,0 +1,50 @@
+// +build go1.13,!plan9
+
+package errors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyFS(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	missing := filepath.Join(dir, "missing")
+	_, notExist := os.Open(missing)
+	exist := os.Mkdir(dir, 0755)
+
+	tests := []struct {
+		err  error
+		code string
+		path string
+	}{
+		{notExist, NotExistCode, missing},
+		{fmt.Errorf("load: %w", notExist), NotExistCode, missing},
+		{exist, ExistCode, dir},
+		{&os.PathError{Op: "open", Path: "/etc/shadow", Err: syscall.EACCES}, PermissionCode, "/etc/shadow"},
+		{&os.PathError{Op: "write", Path: "/data/log", Err: syscall.ENOSPC}, NoSpaceCode, "/data/log"},
+		{&os.LinkError{Op: "rename", Old: "/mnt/a", New: "/mnt/b", Err: syscall.EROFS}, ReadOnlyFSCode, "/mnt/a"},
+		{os.ErrNotExist, NotExistCode, ""},
+		{New("plain"), "", ""},
+		{NewCode("test.fs.coded"), "test.fs.coded", ""},
+	}
+
+	for i, tt := range tests {
+		err := ClassifyFS(tt.err)
+		if got := FirstCode(err); got != tt.code {
+			t.Errorf("test %d: ClassifyFS(%v): got code %q, want %q", i+1, tt.err, got, tt.code)
+		}
+		if got, _ := Params(err)[PathParam].(string); got != tt.path {
+			t.Errorf("test %d: ClassifyFS(%v): got path %q, want %q", i+1, tt.err, got, tt.path)
+		}
+	}
+
+	if ClassifyFS(nil) != nil {
+		t.Errorf("ClassifyFS(nil): want nil")
+	}
+}
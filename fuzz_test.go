@@ -0,0 +1,35 @@
+// +build go1.18
+
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func FuzzFromJSON(f *testing.F) {
+	for _, err := range []error{
+		New("root"),
+		NewCodeWithParams("test.fuzz", map[string]interface{}{"id": 7, "tags": []interface{}{"a"}}, "coded"),
+		WithMessage(WrapCode(io.EOF, "test.fuzz"), "ctx"),
+	} {
+		data, _ := ToJSON(err)
+		f.Add(data)
+	}
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"chain":[{"params":{"a":{"b":{"c":[[[]]]}}}}]}`))
+	f.Add([]byte(`{"chain":[{"code":"x","message":"m","params":{"a":1},"param_types":{"a":""}}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err, perr := FromJSON(data)
+		if perr != nil || err == nil {
+			return
+		}
+
+		// A decoded error must be usable and encode again.
+		_ = err.Error()
+		if _, perr := ToJSON(err); perr != nil {
+			t.Errorf("ToJSON(FromJSON(%q)): %v", data, perr)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// +build go1.18
+
+package errors
+
+// NewCodeT returns an error with the supplied code, message and params, as
+// NewCodeWithParams does, with the params given as a typed struct:
+//
+//     type UserParams struct {
+//             ID int `errparam:"id"`
+//     }
+//
+//     err := errors.NewCodeT("user.not_found", UserParams{ID: 7})
+//
+// The fields of params are converted into the params map by
+// ParamsFromStruct, so that param names are checked at compile time.
+// NewCodeT also records the stack trace at the point it was called.
+func NewCodeT[P any](code string, params P, msgs ...string) error {
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  ParamsFromStruct(params),
+		cause:   checkCode(code, nil),
+		id:      errorID(nil),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// WrapCodeT returns an error annotating err with a stack trace at the point
+// WrapCodeT is called, and the supplied code, message and typed params,
+// as NewCodeT does. If err is nil, WrapCodeT returns nil.
+func WrapCodeT[P any](err error, code string, params P, msgs ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  ParamsFromStruct(params),
+		cause:   err,
+		id:      errorID(err),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// Check returns a function that returns v and err annotated with a stack
+// trace at the point the function is called and the supplied code and
+// message, as WrapCode does. Go only allows multiple return values to be
+// passed as the sole arguments of a call, so the code is passed to the
+// returned function; together this wraps a call returning a value and an
+// error in one expression:
+//
+//     user, err := errors.Check(store.Load(id))("user.load")
+//     if err != nil {
+//             return nil, err
+//     }
+//
+// If err is nil, the returned function returns v and nil.
+func Check[T any](v T, err error) func(code string, msgs ...string) (T, error) {
+	return func(code string, msgs ...string) (T, error) {
+		if err == nil {
+			return v, nil
+		}
+
+		cause := checkCode(code, err)
+
+		return v, &Error{
+			code:    code,
+			message: explicitMessage(msgs),
+			source:  messageSourceOf(msgs),
+			cause:   cause,
+			id:      errorID(cause),
+			seq:     recordWrap(code),
+			stack:   callers(),
+		}
+	}
+}
+
+// Must1 returns v if err is nil, and otherwise panics with err annotated
+// with a stack trace at the point Must1 is called, as WithStack does.
+// It is meant for initialization code where errors are fatal:
+//
+//     var tmpl = errors.Must1(template.ParseFiles("index.html"))
+func Must1[T any](v T, err error) T {
+	if err == nil {
+		return v
+	}
+
+	panic(&withStack{
+		err,
+		callers(),
+	})
+}
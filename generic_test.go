@@ -0,0 +1,82 @@
+// +build go1.18
+
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+type userParams struct {
+	ID       int    `errparam:"id"`
+	Name     string `errparam:"name"`
+	Password string `errparam:"-"`
+	Region   string
+	internal bool
+}
+
+func TestNewCodeT(t *testing.T) {
+	err := NewCodeT("test.generic", userParams{ID: 7, Name: "alice", Password: "secret", Region: "eu"}, "not found")
+
+	want := map[string]interface{}{"id": 7, "name": "alice", "Region": "eu"}
+	if got := Params(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params: got %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "test.generic - not found"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+
+	err = NewCodeT("test.generic", &userParams{ID: 8})
+	if got := Params(err)["id"]; got != 8 {
+		t.Errorf("Params from pointer: got %v, want 8", got)
+	}
+}
+
+func TestWrapCodeT(t *testing.T) {
+	err := WrapCodeT(New("root"), "test.generic", map[string]string{"id": "7"}, "wrapped")
+
+	if got, want := Params(err), map[string]interface{}{"id": "7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Params: got %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "test.generic - wrapped: root"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if WrapCodeT(nil, "test.generic", userParams{}) != nil {
+		t.Errorf("WrapCodeT(nil): want nil")
+	}
+}
+
+func loadValue(v int, err error) (int, error) { return v, err }
+
+func TestCheck(t *testing.T) {
+	v, err := Check(loadValue(7, nil))("test.check")
+	if v != 7 || err != nil {
+		t.Errorf("Check(7, nil): got %d, %v, want 7, nil", v, err)
+	}
+
+	v, err = Check(loadValue(0, io.EOF))("test.check", "load failed")
+	if got, want := err.Error(), "test.check - load failed: EOF"; got != want {
+		t.Errorf("Check(0, EOF): got %q, want %q", got, want)
+	}
+	if got := Frame((*codeOf(err).stack)[0]).name(); got != "github.com/pkg/errors.TestCheck" {
+		t.Errorf("Check(0, EOF): got stack starting at %s, want TestCheck", got)
+	}
+}
+
+func TestMust1(t *testing.T) {
+	if got := Must1(loadValue(7, nil)); got != 7 {
+		t.Errorf("Must1(7, nil): got %d, want 7", got)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || Cause(err) != io.EOF {
+			t.Errorf("Must1(0, EOF): got panic %v, want EOF with stack", err)
+		}
+		if got := err.(*withStack).StackTrace()[0].name(); got != "github.com/pkg/errors.TestMust1" {
+			t.Errorf("Must1(0, EOF): got stack starting at %s, want TestMust1", got)
+		}
+	}()
+	Must1(loadValue(0, io.EOF))
+}
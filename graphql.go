@@ -0,0 +1,66 @@
+package errors
+
+// GraphQLError is an error as it appears in the errors list of a GraphQL
+// response. Its JSON encoding follows the GraphQL specification and matches
+// gqlerror.Error from gqlgen, so it can be copied field by field inside an
+// error presenter.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *GraphQLError) Error() string { return e.Message }
+
+// ToGraphQL converts err into a GraphQL error located at path.
+// The outermost coded error in err's chain provides the message and the
+// code, num_code, params, action and id extensions. Errors without a code
+// keep their Error() text, which matches the default gqlgen presenter.
+// If err is already a *GraphQLError and no path is given, its path is kept.
+// If err is nil, ToGraphQL returns nil.
+//
+// A typical gqlgen presenter looks like:
+//
+//     srv.SetErrorPresenter(func(ctx context.Context, e error) *gqlerror.Error {
+//             ge := errors.ToGraphQL(e)
+//             return &gqlerror.Error{
+//                     Message:    ge.Message,
+//                     Path:       graphql.GetPath(ctx),
+//                     Extensions: ge.Extensions,
+//             }
+//     })
+func ToGraphQL(err error, path ...interface{}) *GraphQLError {
+	if err == nil {
+		return nil
+	}
+
+	if ge, ok := err.(*GraphQLError); ok && len(path) == 0 {
+		path = ge.Path
+	}
+
+	wc := codeOf(err)
+	if wc == nil {
+		return &GraphQLError{Message: err.Error(), Path: path}
+	}
+
+	l := serializationLimits()
+	extensions := map[string]interface{}{"code": wc.code}
+	if n, ok := numCode(wc); ok {
+		extensions["num_code"] = n
+	}
+	if len(wc.params) > 0 {
+		extensions["params"] = l.limitParams(wc.params)
+	}
+	if action := wc.Action(); action != "" {
+		extensions["action"] = action
+	}
+	if wc.id != "" {
+		extensions["id"] = wc.id
+	}
+
+	return &GraphQLError{
+		Message:    l.limitMessage(wc.Message()),
+		Path:       path,
+		Extensions: extensions,
+	}
+}
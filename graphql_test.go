@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToGraphQL(t *testing.T) {
+	Register(testCoder{code: "test.graphql", status: 404, message: "not found"})
+
+	tests := []struct {
+		err  error
+		path []interface{}
+		want string
+	}{
+		{New("boom"), nil, `{"message":"boom"}`},
+		{NewCode("test.graphql"), []interface{}{"user", 0}, `{"message":"not found","path":["user",0],"extensions":{"code":"test.graphql"}}`},
+		{Wrap(NewCodeWithParams("test.graphql", map[string]interface{}{"id": 1}), "lookup"), nil, `{"message":"not found","extensions":{"code":"test.graphql","params":{"id":1}}}`},
+		{&GraphQLError{Message: "bad", Path: []interface{}{"a"}}, nil, `{"message":"bad","path":["a"]}`},
+	}
+
+	for i, tt := range tests {
+		got, err := json.Marshal(ToGraphQL(tt.err, tt.path...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("test %d: ToGraphQL(%v):\n got %s\n want %s", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if ToGraphQL(nil) != nil {
+		t.Errorf("ToGraphQL(nil): want nil")
+	}
+}
@@ -0,0 +1,210 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpBody is the JSON body written by WriteHTTP.
+type httpBody struct {
+	Code      string                 `json:"code,omitempty"`
+	NumCode   *int                   `json:"num_code,omitempty"`
+	Message   string                 `json:"message"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Action    string                 `json:"action,omitempty"`
+	Reference string                 `json:"reference,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+}
+
+// WriteHTTP writes err to w as a JSON response.
+// The outermost coded error in err's chain provides the body, and the status
+// code of its registered Coder is used as the HTTP status. Errors without a
+// code, or with an unregistered code, are written as 500 Internal Server Error;
+// the text of errors without a code is not exposed.
+// For 429 Too Many Requests and 503 Service Unavailable responses, the delay
+// reported by RetryAfter is sent in the Retry-After header.
+func WriteHTTP(w http.ResponseWriter, err error) {
+	writeHTTP(w, err, nil, jsonContentType, JSONEncoder)
+}
+
+// WriteHTTPLocalized writes err to w as WriteHTTP does, with the message
+// translated into the most preferred language of the request's
+// Accept-Language header that the Translator has a translation for.
+// The chosen language is sent in the Content-Language header.
+//
+// The body is encoded in the media type the request's Accept header
+// prefers among those registered with RegisterEncoder: JSON, problem+json
+// and XML by default. Requests that accept none of them get JSON.
+func WriteHTTPLocalized(w http.ResponseWriter, r *http.Request, err error) {
+	contentType, enc := negotiate(r.Header.Get("Accept"))
+	writeHTTP(w, err, acceptedLanguages(r.Header.Get("Accept-Language")), contentType, enc)
+}
+
+// HandlerE is an HTTP handler that returns an error instead of writing an
+// error response itself.
+type HandlerE func(w http.ResponseWriter, r *http.Request) error
+
+// Handler returns an http.Handler that calls h and writes the error it
+// returns, if any, as WriteHTTPLocalized does:
+//
+//     http.Handle("/users/", errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//             user, err := store.Load(r.URL.Path)
+//             if err != nil {
+//                     return errors.WrapCode(err, "user.not_found")
+//             }
+//             return json.NewEncoder(w).Encode(user)
+//     }, errors.NewStderrReporter()))
+//
+// The error is also reported to each of reporters with the context of the
+// request, before the response is written. Handlers that have already
+// started writing a response should not return an error.
+func Handler(h HandlerE, reporters ...Reporter) http.Handler {
+	report := FanOut(reporters...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+
+		report.Report(r.Context(), err)
+		WriteHTTPLocalized(w, r, err)
+	})
+}
+
+func writeHTTP(w http.ResponseWriter, err error, locales []string, contentType string, enc Encoder) {
+	status, _ := httpResponse(err)
+	setRetryAfter(w.Header(), status, err)
+
+	if wc := codeOf(err); wc != nil {
+		if msg, locale, ok := translate(wc, locales); ok {
+			// The Encoder is given the whole chain, with the message of
+			// its outermost coded error localized.
+			localize := func(wc *Error) {
+				wc.message = msg
+				wc.source = messageExplicit
+			}
+			if localized, ok := updateCoded(err, localize, 0); ok {
+				err = localized
+			} else {
+				localized := wc.clone()
+				localize(&localized)
+				err = &localized
+			}
+			w.Header().Set("Content-Language", locale)
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	enc.Encode(w, err)
+}
+
+// RenderHTTP returns the HTTP status, headers and JSON body that WriteHTTP
+// writes for err, for frameworks whose responses are not written through an
+// http.ResponseWriter.
+func RenderHTTP(err error) (int, http.Header, []byte) {
+	status, body := httpResponse(err)
+
+	h := http.Header{}
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	setRetryAfter(h, status, err)
+
+	data, _ := json.Marshal(&body)
+	return status, h, data
+}
+
+// setRetryAfter sets the Retry-After header of 429 Too Many Requests and
+// 503 Service Unavailable responses to the delay reported by RetryAfter.
+func setRetryAfter(h http.Header, status int, err error) {
+	if status != http.StatusTooManyRequests && status != http.StatusServiceUnavailable {
+		return
+	}
+
+	if after, ok := RetryAfter(err); ok {
+		seconds := int64((after + time.Second - 1) / time.Second)
+		h.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
+
+// httpResponse returns the HTTP status and JSON body for err.
+func httpResponse(err error) (int, httpBody) {
+	status := http.StatusInternalServerError
+	body := httpBody{Message: http.StatusText(status)}
+
+	if wc := codeOf(err); wc != nil {
+		l := serializationLimits()
+		body = httpBody{
+			Code:    wc.code,
+			Message: l.limitMessage(wc.Message()),
+			Params:  l.limitParams(wc.params),
+			Action:  l.limitMessage(wc.Action()),
+			ID:      wc.id,
+		}
+		if coder := wc.Coder(); coder != nil {
+			status = coder.StatusCode()
+			body.Reference = coder.Reference()
+		}
+		if n, ok := numCode(wc); ok {
+			body.NumCode = &n
+		}
+	}
+
+	return status, body
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header
+// value, most preferred first. A region specific tag such as "de-CH" is
+// followed by its base language "de".
+func acceptedLanguages(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, q := strings.TrimSpace(part), 1.0
+		if i := strings.IndexByte(tag, ';'); i >= 0 {
+			param := strings.TrimSpace(tag[i+1:])
+			tag = strings.TrimSpace(tag[:i])
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if tag == "" || tag == "*" || q <= 0 {
+			continue
+		}
+
+		languages = append(languages, language{tag: tag, q: q})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	var tags []string
+	seen := map[string]bool{}
+	for _, l := range languages {
+		tags = appendLanguage(tags, seen, l.tag)
+		if i := strings.IndexByte(l.tag, '-'); i > 0 {
+			tags = appendLanguage(tags, seen, l.tag[:i])
+		}
+	}
+
+	return tags
+}
+
+func appendLanguage(tags []string, seen map[string]bool, tag string) []string {
+	if seen[tag] {
+		return tags
+	}
+
+	seen[tag] = true
+	return append(tags, tag)
+}
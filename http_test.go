@@ -0,0 +1,156 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type actionCoder struct {
+	testCoder
+	action string
+}
+
+func (c actionCoder) Action() string { return c.action }
+
+func TestWriteHTTP(t *testing.T) {
+	Register(testCoder{code: "test.http.notfound", status: http.StatusNotFound, message: "not found"})
+	Register(actionCoder{testCoder{code: "test.http.busy", status: http.StatusServiceUnavailable, message: "busy"}, "retry request {id} later"})
+
+	tests := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{New("secret"), 500, `{"message":"Internal Server Error"}`},
+		{Wrap(NewCode("test.http.notfound"), "lookup"), 404, `{"code":"test.http.notfound","message":"not found"}`},
+		{NewCodeWithParams("test.http.busy", map[string]interface{}{"id": 42}), 503, `{"code":"test.http.busy","message":"busy","params":{"id":42},"action":"retry request 42 later"}`},
+		{NewCode("test.http.unregistered", "oops"), 500, `{"code":"test.http.unregistered","message":"oops"}`},
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, tt.err)
+		if rec.Code != tt.status {
+			t.Errorf("test %d: status: got %d, want %d", i+1, rec.Code, tt.status)
+		}
+		if got := rec.Body.String(); got != tt.body+"\n" {
+			t.Errorf("test %d: body:\n got %s\n want %s", i+1, got, tt.body)
+		}
+	}
+}
+
+func TestAcceptedLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", nil},
+		{"de", []string{"de"}},
+		{"fr;q=0.5, de-CH, en;q=0.8, *;q=0.1", []string{"de-CH", "de", "en", "fr"}},
+		{"en;q=0, it", []string{"it"}},
+	}
+
+	for _, tt := range tests {
+		if got := acceptedLanguages(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("acceptedLanguages(%q): got %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestWriteHTTPLocalized(t *testing.T) {
+	Register(testCoder{code: "test.http.l10n", status: http.StatusBadRequest, message: "bad request"})
+	SetTranslator(mapTranslator{"de/test.http.l10n": "Ungültige Anfrage"})
+	defer SetTranslator(nil)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr-CH, de;q=0.9, en;q=0.8")
+	rec := httptest.NewRecorder()
+	WriteHTTPLocalized(rec, r, NewCode("test.http.l10n"))
+
+	if got, want := rec.Body.String(), `{"code":"test.http.l10n","message":"Ungültige Anfrage"}`+"\n"; got != want {
+		t.Errorf("body: got %s, want %s", got, want)
+	}
+	if got := rec.Header().Get("Content-Language"); got != "de" {
+		t.Errorf("Content-Language: got %q, want %q", got, "de")
+	}
+
+	// The Encoder is given the chain with its wrappers.
+	var encoded error
+	RegisterEncoder("application/x-test", EncoderFunc(func(w io.Writer, err error) error {
+		encoded = err
+		return nil
+	}))
+	defer RegisterEncoder("application/x-test", nil)
+	r.Header.Set("Accept", "application/x-test")
+	WriteHTTPLocalized(httptest.NewRecorder(), r, WithUpstream(WithRetryAfter(NewCode("test.http.l10n"), time.Second), "billing", "", 0))
+	if _, ok := UpstreamOf(encoded); !ok {
+		t.Errorf("Encoder: got %v, want the WithUpstream annotation kept", encoded)
+	}
+	if _, ok := RetryAfter(encoded); !ok {
+		t.Errorf("Encoder: got %v, want the WithRetryAfter annotation kept", encoded)
+	}
+	if got, want := encoded.Error(), "test.http.l10n - Ungültige Anfrage"; got != want {
+		t.Errorf("Encoder: got %q, want %q", got, want)
+	}
+	r.Header.Del("Accept")
+
+	r.Header.Set("Accept-Language", "fr")
+	rec = httptest.NewRecorder()
+	WriteHTTPLocalized(rec, r, NewCode("test.http.l10n"))
+	if got, want := rec.Body.String(), `{"code":"test.http.l10n","message":"bad request"}`+"\n"; got != want {
+		t.Errorf("body: got %s, want %s", got, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	Register(testCoder{code: "test.http.handler", status: http.StatusConflict, message: "conflict"})
+
+	var reports recordReporter
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("ok"))
+			return nil
+		}
+		return WrapCode(New("version mismatch"), "test.http.handler")
+	}, &reports)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("/ok: got %d %q, want 200 %q", rec.Code, rec.Body.String(), "ok")
+	}
+	if len(reports.errs) != 0 {
+		t.Errorf("/ok: reported %v, want nothing", reports.errs)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/users/7", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("/users/7: status: got %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if got, want := rec.Body.String(), `{"code":"test.http.handler","message":"conflict"}`+"\n"; got != want {
+		t.Errorf("/users/7: body:\n got %s\n want %s", got, want)
+	}
+	if len(reports.errs) != 1 || Code(reports.errs[0]) != "test.http.handler" {
+		t.Errorf("/users/7: reported %v, want one test.http.handler error", reports.errs)
+	}
+}
+
+func TestRenderHTTP(t *testing.T) {
+	Register(testCoder{code: "test.http.render", status: http.StatusTooManyRequests, message: "slow down"})
+
+	status, header, body := RenderHTTP(WithRetryAfter(NewCode("test.http.render"), time.Second))
+	if status != http.StatusTooManyRequests {
+		t.Errorf("status: got %d, want %d", status, http.StatusTooManyRequests)
+	}
+	if got := header.Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After: got %q, want %q", got, "1")
+	}
+	if got, want := string(body), `{"code":"test.http.render","message":"slow down"}`; got != want {
+		t.Errorf("body:\n got %s\n want %s", got, want)
+	}
+}
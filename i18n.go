@@ -0,0 +1,115 @@
+package errors
+
+import "sync"
+
+// Translator provides localized messages for error codes, for example from
+// a go-i18n bundle or another message catalog. Translate returns the message
+// of code in the language of locale with params filled in, and reports
+// whether a translation was found.
+type Translator interface {
+	Translate(locale, code string, params map[string]interface{}) (string, bool)
+}
+
+// ParamFormatter formats param values for display in the language of
+// locale, for example numbers with locale specific digit grouping or dates
+// in the local order. Values it does not handle are returned unchanged.
+type ParamFormatter interface {
+	FormatParam(locale string, v interface{}) interface{}
+}
+
+var translator Translator
+var paramFormatter ParamFormatter
+var translatorMux = &sync.RWMutex{}
+
+// SetTranslator sets the Translator used by LocalizedMessage.
+// A nil Translator disables localization.
+func SetTranslator(t Translator) {
+	translatorMux.Lock()
+	defer translatorMux.Unlock()
+
+	translator = t
+}
+
+// SetParamFormatter sets the ParamFormatter applied to params before they are
+// passed to the Translator, and by LocalizedFullMessage.
+// A nil ParamFormatter leaves params unchanged.
+func SetParamFormatter(f ParamFormatter) {
+	translatorMux.Lock()
+	defer translatorMux.Unlock()
+
+	paramFormatter = f
+}
+
+// LocalizedMessage returns the message of the outermost coded error in err's
+// chain translated into the language of locale. If no Translator is set or
+// it has no translation, the untranslated message is returned.
+// If err has no code, the empty string is returned.
+func LocalizedMessage(err error, locale string) string {
+	wc := codeOf(err)
+	if wc == nil {
+		return ""
+	}
+
+	if msg, _, ok := translate(wc, []string{locale}); ok {
+		return msg
+	}
+
+	return wc.Message()
+}
+
+// LocalizedFullMessage returns the full message of the outermost coded error
+// in err's chain, as FullMessage does, with the message translated into the
+// language of locale and the params formatted by the ParamFormatter.
+// If err has no code, the empty string is returned.
+func LocalizedFullMessage(err error, locale string) string {
+	wc := codeOf(err)
+	if wc == nil {
+		return ""
+	}
+
+	localized := wc.clone()
+	localized.message = LocalizedMessage(wc, locale)
+	localized.source = messageExplicit
+	localized.params = localizeParams(locale, wc.params)
+
+	return localized.FullMessage()
+}
+
+// localizeParams returns a copy of params formatted for locale by the
+// ParamFormatter, or params itself if there is none.
+func localizeParams(locale string, params map[string]interface{}) map[string]interface{} {
+	translatorMux.RLock()
+	f := paramFormatter
+	translatorMux.RUnlock()
+
+	if f == nil || len(params) == 0 {
+		return params
+	}
+
+	localized := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		localized[k] = f.FormatParam(locale, v)
+	}
+
+	return localized
+}
+
+// translate returns the message of wc in the first of locales that the
+// Translator has a translation for, and that locale.
+func translate(wc *Error, locales []string) (string, string, bool) {
+	translatorMux.RLock()
+	t := translator
+	translatorMux.RUnlock()
+
+	if t == nil {
+		return "", "", false
+	}
+
+	for _, locale := range locales {
+		if msg, ok := t.Translate(locale, wc.code, localizeParams(locale, wc.params)); ok {
+			return msg, locale, true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,32 @@
+// +build goi18n
+
+package errors
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// I18nTranslator is a Translator backed by a go-i18n bundle. Message IDs are
+// error codes and params are passed as template data; a "count" param selects
+// the plural form.
+type I18nTranslator struct {
+	Bundle *i18n.Bundle
+}
+
+// NewI18nTranslator returns a Translator reading messages from bundle.
+func NewI18nTranslator(bundle *i18n.Bundle) *I18nTranslator {
+	return &I18nTranslator{Bundle: bundle}
+}
+
+func (t *I18nTranslator) Translate(locale, code string, params map[string]interface{}) (string, bool) {
+	msg, err := i18n.NewLocalizer(t.Bundle, locale).Localize(&i18n.LocalizeConfig{
+		MessageID:    code,
+		TemplateData: params,
+		PluralCount:  params["count"],
+	})
+	if err != nil {
+		return "", false
+	}
+
+	return msg, true
+}
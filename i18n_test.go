@@ -0,0 +1,65 @@
+package errors
+
+import "testing"
+
+type mapTranslator map[string]string
+
+func (m mapTranslator) Translate(locale, code string, params map[string]interface{}) (string, bool) {
+	msg, ok := m[locale+"/"+code]
+	return expand(msg, params), ok
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	SetTranslator(mapTranslator{"de/test.i18n": "Datei {name} nicht gefunden"})
+	defer SetTranslator(nil)
+
+	err := Wrap(NewCodeWithParams("test.i18n", map[string]interface{}{"name": "a.txt"}, "file not found"), "open")
+
+	tests := []struct {
+		err    error
+		locale string
+		want   string
+	}{
+		{err, "de", "Datei a.txt nicht gefunden"},
+		{err, "fr", "file not found"},
+		{New("plain"), "de", ""},
+	}
+
+	for i, tt := range tests {
+		if got := LocalizedMessage(tt.err, tt.locale); got != tt.want {
+			t.Errorf("test %d: LocalizedMessage(%q): got %q, want %q", i+1, tt.locale, got, tt.want)
+		}
+	}
+}
+
+type localeFormatter struct{}
+
+func (localeFormatter) FormatParam(locale string, v interface{}) interface{} {
+	if n, ok := v.(int); ok {
+		return locale + ":" + string(rune('0'+n))
+	}
+
+	return v
+}
+
+func TestLocalizedFullMessage(t *testing.T) {
+	SetTranslator(mapTranslator{"de/test.i18n.params": "{count} Dateien in {dir}"})
+	SetParamFormatter(localeFormatter{})
+	defer SetTranslator(nil)
+	defer SetParamFormatter(nil)
+
+	err := NewCodeWithParams("test.i18n.params", map[string]interface{}{"count": 3, "dir": "tmp"}, "{count} files in {dir}")
+
+	if got, want := LocalizedMessage(err, "de"), "de:3 Dateien in tmp"; got != want {
+		t.Errorf("LocalizedMessage: got %q, want %q", got, want)
+	}
+	if got, want := LocalizedFullMessage(err, "de"), `{"params":{"count":"de:3","dir":"tmp"},"message":"de:3 Dateien in tmp"}`; got != want {
+		t.Errorf("LocalizedFullMessage: got %s, want %s", got, want)
+	}
+	if got, want := FullMessage(err), `{"params":{"count":3,"dir":"tmp"},"message":"{count} files in {dir}"}`; got != want {
+		t.Errorf("FullMessage: got %s, want %s", got, want)
+	}
+	if got := LocalizedFullMessage(New("plain"), "de"); got != "" {
+		t.Errorf("LocalizedFullMessage(plain): got %q, want empty", got)
+	}
+}
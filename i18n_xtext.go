@@ -0,0 +1,64 @@
+// +build xtext
+
+package errors
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	textmessage "golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// TextParamFormatter is a ParamFormatter backed by golang.org/x/text.
+// Numbers are formatted with the digit grouping and decimal separator of the
+// locale, dates with the layout of the locale's base language in DateLayouts,
+// and durations are rounded to the second.
+type TextParamFormatter struct {
+	// DateLayouts maps base languages, such as "de", to time layouts.
+	// Languages without a layout use DefaultDateLayout.
+	DateLayouts map[string]string
+
+	// DefaultDateLayout is the layout of dates in other languages.
+	DefaultDateLayout string
+}
+
+// NewTextParamFormatter returns a TextParamFormatter with date layouts for
+// common languages.
+func NewTextParamFormatter() *TextParamFormatter {
+	return &TextParamFormatter{
+		DateLayouts: map[string]string{
+			"en": "Jan 2, 2006",
+			"de": "2.1.2006",
+			"fr": "02/01/2006",
+			"es": "02/01/2006",
+			"it": "02/01/2006",
+			"ja": "2006/01/02",
+			"zh": "2006/01/02",
+		},
+		DefaultDateLayout: "2006-01-02",
+	}
+}
+
+func (f *TextParamFormatter) FormatParam(locale string, v interface{}) interface{} {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return v
+	}
+
+	switch x := v.(type) {
+	case time.Time:
+		base, _ := tag.Base()
+		layout, ok := f.DateLayouts[base.String()]
+		if !ok {
+			layout = f.DefaultDateLayout
+		}
+		return x.Format(layout)
+	case time.Duration:
+		return x.Round(time.Second).String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return textmessage.NewPrinter(tag).Sprint(number.Decimal(x))
+	}
+
+	return v
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// IDGenerator, if set, is called to assign a unique instance ID to every
+// coded error at construction. Wrapping a coded error keeps the ID of the
+// wrapped error, so the whole chain shares one ID. IDs are not generated,
+// nor kept by wrapping, by default; enable them with
+//
+//     errors.IDGenerator = errors.NewUUID
+//
+// IDGenerator should be set during program initialization.
+var IDGenerator func() string
+
+// NewUUID returns a random (version 4) UUID. It is meant to be used as
+// IDGenerator.
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// ErrorID returns the instance ID of the outermost coded error in err's
+// chain that has one. If there is none, the empty string is returned.
+func ErrorID(err error) string {
+	id := ""
+	walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok {
+			id = wc.id
+		}
+
+		return id == ""
+	})
+
+	return id
+}
+
+// errorID returns the instance ID for a coded error wrapping cause. The
+// chain of cause is not walked while IDs are disabled, as constructors call
+// errorID for every coded error.
+func errorID(cause error) string {
+	if IDGenerator == nil {
+		return ""
+	}
+
+	if id := ErrorID(cause); id != "" {
+		return id
+	}
+
+	return IDGenerator()
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestErrorID(t *testing.T) {
+	if got := ErrorID(NewCode("test.id")); got != "" {
+		t.Errorf("ErrorID without generator: got %q, want empty", got)
+	}
+
+	n := 0
+	IDGenerator = func() string {
+		n++
+		return fmt.Sprintf("id-%d", n)
+	}
+	defer func() { IDGenerator = nil }()
+
+	err := NewCode("test.id", "failed")
+	if got, want := ErrorID(err), "id-1"; got != want {
+		t.Errorf("ErrorID: got %q, want %q", got, want)
+	}
+
+	wrapped := WrapCode(Wrap(err, "io"), "test.outer", "outer")
+	if got, want := ErrorID(wrapped), "id-1"; got != want {
+		t.Errorf("ErrorID(wrapped): got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%+v", wrapped); !strings.Contains(got, "test.outer - outer [id-1]") {
+		t.Errorf("%%+v: got %q, want id on outermost layer", got)
+	}
+
+	if got, want := ErrorID(WrapCode(New("root"), "test.id")), "id-2"; got != want {
+		t.Errorf("ErrorID: got %q, want %q", got, want)
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := NewUUID(), NewUUID()
+	if !re.MatchString(a) {
+		t.Errorf("NewUUID: got %q, want version 4 UUID", a)
+	}
+	if a == b {
+		t.Errorf("NewUUID: got %q twice", a)
+	}
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// registryGeneration is incremented whenever the registry changes, so that
+// the Coders resolved by LazyCoder proxies are looked up again.
+var registryGeneration uint64
+
+// LazyCoder returns a Coder for code that resolves the registered Coder of
+// code when it is first used, instead of when LazyCoder is called. Package
+// level variables can so refer to codes registered by the init functions of
+// other packages, whatever the order in which the packages are initialized:
+//
+//     var ErrQuota = errors.LazyCoder("billing.quota_exceeded")
+//
+//     return errors.FromCoder(ErrQuota)
+//
+// The resolved Coder is kept until the registry changes. While code is not
+// registered, the proxy reports a 500 Internal Server Error status and the
+// message of MessageFallback. Coded errors created from the proxy by
+// FromCoder return the resolved Coder from their Coder method, so that the
+// optional interfaces of the Coder, such as Flagger, are found.
+func LazyCoder(code string) Coder {
+	return &lazyCoder{code: code}
+}
+
+type lazyCoder struct {
+	code     string
+	resolved atomic.Value // lazyResolution
+}
+
+type lazyResolution struct {
+	coder      Coder
+	generation uint64
+}
+
+// resolve returns the registered Coder of c's code, or nil if there is none.
+func (c *lazyCoder) resolve() Coder {
+	generation := atomic.LoadUint64(&registryGeneration)
+	if r, ok := c.resolved.Load().(lazyResolution); ok && r.generation == generation {
+		return r.coder
+	}
+
+	coder := GetCoder(c.code)
+	if coder == Coder(c) {
+		coder = nil
+	}
+	if coder != nil {
+		c.resolved.Store(lazyResolution{coder: coder, generation: generation})
+	}
+
+	return coder
+}
+
+func (c *lazyCoder) Code() string { return c.code }
+
+func (c *lazyCoder) StatusCode() int {
+	if coder := c.resolve(); coder != nil {
+		return coder.StatusCode()
+	}
+
+	return http.StatusInternalServerError
+}
+
+func (c *lazyCoder) Message() string {
+	if coder := c.resolve(); coder != nil {
+		return coder.Message()
+	}
+	if MessageFallback != nil {
+		return MessageFallback(c.code)
+	}
+
+	return ""
+}
+
+func (c *lazyCoder) Params() map[string]interface{} {
+	if coder := c.resolve(); coder != nil {
+		return coder.Params()
+	}
+
+	return nil
+}
+
+func (c *lazyCoder) FullMessage() string {
+	if coder := c.resolve(); coder != nil {
+		return coder.FullMessage()
+	}
+
+	return c.Message()
+}
+
+func (c *lazyCoder) Reference() string {
+	if coder := c.resolve(); coder != nil {
+		return coder.Reference()
+	}
+
+	return ""
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+// lazyQuota is resolved after the code is registered below, as a package
+// variable of another package would be.
+var lazyQuota = LazyCoder("test.lazy.quota")
+
+func TestLazyCoder(t *testing.T) {
+	if got := lazyQuota.Code(); got != "test.lazy.quota" {
+		t.Errorf("Code: got %q", got)
+	}
+	if got := lazyQuota.StatusCode(); got != http.StatusInternalServerError {
+		t.Errorf("unregistered StatusCode: got %d, want 500", got)
+	}
+	if got := lazyQuota.Message(); got != "" {
+		t.Errorf("unregistered Message: got %q, want empty", got)
+	}
+	MessageFallback = CodeAsMessage
+	if got := lazyQuota.FullMessage(); got != "test.lazy.quota" {
+		t.Errorf("unregistered FullMessage: got %q, want the code", got)
+	}
+	MessageFallback = nil
+
+	Register(flagCoder{testCoder{code: "test.lazy.quota", status: http.StatusTooManyRequests, message: "quota exceeded"}, FlagRetryable})
+
+	if got := lazyQuota.StatusCode(); got != http.StatusTooManyRequests {
+		t.Errorf("StatusCode: got %d, want 429", got)
+	}
+	if got := lazyQuota.Message(); got != "quota exceeded" {
+		t.Errorf("Message: got %q", got)
+	}
+
+	err := FromCoder(lazyQuota)
+	if got := err.Error(); got != "test.lazy.quota - quota exceeded" {
+		t.Errorf("FromCoder: got %q", got)
+	}
+	if !HasFlag(err, FlagRetryable) {
+		t.Errorf("FromCoder: the flags of the resolved Coder are lost")
+	}
+
+	// Registering the code again is picked up by the proxy.
+	Register(testCoder{code: "test.lazy.quota", status: http.StatusPaymentRequired, message: "pay up"})
+	if got := lazyQuota.Message(); got != "pay up" {
+		t.Errorf("Message after Register: got %q, want %q", got, "pay up")
+	}
+	if HasFlag(err, FlagRetryable) {
+		t.Errorf("Coder after Register: got the replaced Coder")
+	}
+}
+
+func TestLazyCoderRegisteredItself(t *testing.T) {
+	c := LazyCoder("test.lazy.self")
+	Register(c)
+
+	if got := c.StatusCode(); got != http.StatusInternalServerError {
+		t.Errorf("StatusCode: got %d, want 500", got)
+	}
+}
+
+func BenchmarkLazyCoder(b *testing.B) {
+	Register(testCoder{code: "test.lazy.bench", message: "bench"})
+	c := LazyCoder("test.lazy.bench")
+
+	for i := 0; i < b.N; i++ {
+		c.Message()
+	}
+}
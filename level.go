@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Level is the severity at which an error should be logged. The values of
+// the Level constants are those of the corresponding log/slog levels, so
+// that slog.Level(LevelOf(err)) can be passed to a slog.Logger.
+type Level int
+
+// The levels returned by LevelOf.
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+// String returns the name of l, such as "INFO".
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "Level(" + strconv.Itoa(int(l)) + ")"
+}
+
+var levelMapping func(Coder) Level
+var levelMux = &sync.RWMutex{}
+
+// SetLevelMapping sets the function LevelOf uses to map the Coder of an
+// error to a Level. The Coder passed to fn is nil for errors without a
+// registered code.
+// A nil fn restores the default mapping, which logs errors whose Coder has
+// a 4xx status at LevelInfo and all other errors at LevelError.
+func SetLevelMapping(fn func(Coder) Level) {
+	levelMux.Lock()
+	defer levelMux.Unlock()
+
+	levelMapping = fn
+}
+
+// LevelOf returns the Level at which err should be logged, as mapped from
+// the Coder of the outermost coded error in err's chain by the function set
+// with SetLevelMapping. This allows logging middleware to log expected
+// errors, such as not found or validation errors, below failures:
+//
+//     logger.Log(ctx, slog.Level(errors.LevelOf(err)), "request failed", "error", err)
+//
+// If err is nil, LevelOf returns LevelInfo.
+func LevelOf(err error) Level {
+	if err == nil {
+		return LevelInfo
+	}
+
+	var coder Coder
+	if wc := codeOf(err); wc != nil {
+		coder = wc.Coder()
+	}
+
+	levelMux.RLock()
+	fn := levelMapping
+	levelMux.RUnlock()
+
+	if fn == nil {
+		fn = defaultLevel
+	}
+	return fn(coder)
+}
+
+func defaultLevel(coder Coder) Level {
+	if coder == nil {
+		return LevelError
+	}
+
+	status := coder.StatusCode()
+	if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		return LevelInfo
+	}
+	return LevelError
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestLevelOf(t *testing.T) {
+	Register(testCoder{code: "test.level.notfound", status: http.StatusNotFound})
+	Register(testCoder{code: "test.level.internal", status: http.StatusInternalServerError})
+	Register(severityCoder{testCoder{code: "test.level.degraded", status: http.StatusOK}, "warning"})
+
+	tests := []struct {
+		err  error
+		want Level
+	}{
+		{nil, LevelInfo},
+		{io.EOF, LevelError},
+		{NewCode("test.level.unregistered"), LevelError},
+		{Wrap(NewCode("test.level.notfound"), "lookup"), LevelInfo},
+		{NewCode("test.level.internal"), LevelError},
+		{NewCode("test.level.degraded"), LevelError},
+	}
+
+	for i, tt := range tests {
+		if got := LevelOf(tt.err); got != tt.want {
+			t.Errorf("test %d: LevelOf(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	SetLevelMapping(func(c Coder) Level {
+		if s, ok := c.(SeverityCoder); ok && s.Severity() == "warning" {
+			return LevelWarn
+		}
+		return LevelDebug
+	})
+	defer SetLevelMapping(nil)
+
+	if got := LevelOf(NewCode("test.level.degraded")); got != LevelWarn {
+		t.Errorf("LevelOf(degraded): got %v, want %v", got, LevelWarn)
+	}
+	if got := LevelOf(io.EOF); got != LevelDebug {
+		t.Errorf("LevelOf(EOF): got %v, want %v", got, LevelDebug)
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelDebug, "DEBUG"},
+		{LevelInfo, "INFO"},
+		{LevelWarn, "WARN"},
+		{LevelError, "ERROR"},
+		{Level(2), "Level(2)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.want {
+			t.Errorf("Level(%d).String(): got %q, want %q", int(tt.level), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package errors
+
+import "sort"
+
+// Lifecycle is the stage of a code in the lifecycle of the code catalog.
+type Lifecycle int
+
+const (
+	// LifecycleActive is the stage of codes in use. It is the stage of
+	// Coders that do not implement Lifecycler.
+	LifecycleActive Lifecycle = iota
+
+	// LifecycleDraft is the stage of codes that are not yet in use, such
+	// as codes of features under development.
+	LifecycleDraft
+
+	// LifecycleDeprecated is the stage of codes that are still in use but
+	// are being replaced.
+	LifecycleDeprecated
+
+	// LifecycleRetired is the stage of codes that are no longer in use.
+	// They stay registered so that errors received from older services can
+	// still be decoded, but they cannot be constructed in strict mode.
+	LifecycleRetired
+)
+
+func (l Lifecycle) String() string {
+	switch l {
+	case LifecycleActive:
+		return "active"
+	case LifecycleDraft:
+		return "draft"
+	case LifecycleDeprecated:
+		return "deprecated"
+	case LifecycleRetired:
+		return "retired"
+	}
+
+	return "unknown"
+}
+
+// Lifecycler is implemented by Coders that carry their lifecycle stage.
+type Lifecycler interface {
+	Lifecycle() Lifecycle
+}
+
+// RetiredCode is the code of the errors inserted by StrictReplace for
+// errors created with a retired code.
+const RetiredCode = "retired_code"
+
+// LifecycleOf returns the lifecycle stage of the registered Coder of code.
+// Codes whose Coder does not implement Lifecycler are active; codes that
+// are not registered are drafts.
+func LifecycleOf(code string) Lifecycle {
+	coder := GetCoder(code)
+	if coder == nil {
+		return LifecycleDraft
+	}
+
+	return lifecycleOf(coder)
+}
+
+func lifecycleOf(coder Coder) Lifecycle {
+	if l, ok := coder.(Lifecycler); ok {
+		return l.Lifecycle()
+	}
+
+	return LifecycleActive
+}
+
+// CodesIn returns the sorted registered codes in the lifecycle stage l,
+// for example to list the deprecated codes still to be migrated.
+func CodesIn(l Lifecycle) []string {
+	codeMux.RLock()
+	var found []string
+	for code, coder := range codes {
+		if lifecycleOf(coder) == l {
+			found = append(found, code)
+		}
+	}
+	codeMux.RUnlock()
+
+	sort.Strings(found)
+	return found
+}
@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type lifecycleCoder struct {
+	testCoder
+	lifecycle Lifecycle
+}
+
+func (c lifecycleCoder) Lifecycle() Lifecycle { return c.lifecycle }
+
+func TestLifecycle(t *testing.T) {
+	withRegistry(func() {
+		tests := []struct {
+			code string
+			want Lifecycle
+		}{
+			{"test.lifecycle.plain", LifecycleActive},
+			{"test.lifecycle.draft", LifecycleDraft},
+			{"test.lifecycle.old", LifecycleDeprecated},
+			{"test.lifecycle.gone", LifecycleRetired},
+			{"test.lifecycle.unknown", LifecycleDraft},
+		}
+
+		for _, tt := range tests {
+			if got := LifecycleOf(tt.code); got != tt.want {
+				t.Errorf("LifecycleOf(%q): got %v, want %v", tt.code, got, tt.want)
+			}
+		}
+
+		if got, want := CodesIn(LifecycleDeprecated), []string{"test.lifecycle.old", "test.lifecycle.older"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CodesIn(deprecated): got %v, want %v", got, want)
+		}
+		if got := CodesIn(LifecycleDraft); !reflect.DeepEqual(got, []string{"test.lifecycle.draft"}) {
+			t.Errorf("CodesIn(draft): got %v", got)
+		}
+	},
+		testCoder{code: "test.lifecycle.plain"},
+		lifecycleCoder{testCoder{code: "test.lifecycle.draft"}, LifecycleDraft},
+		lifecycleCoder{testCoder{code: "test.lifecycle.older"}, LifecycleDeprecated},
+		lifecycleCoder{testCoder{code: "test.lifecycle.old"}, LifecycleDeprecated},
+		lifecycleCoder{testCoder{code: "test.lifecycle.gone"}, LifecycleRetired},
+	)
+
+	if got := Lifecycle(42).String(); got != "unknown" {
+		t.Errorf("String(): got %q, want unknown", got)
+	}
+}
+
+func TestStrictRetiredCodes(t *testing.T) {
+	Register(lifecycleCoder{testCoder{code: "test.lifecycle.retired", message: "gone"}, LifecycleRetired})
+	Register(lifecycleCoder{testCoder{code: "test.lifecycle.deprecated", message: "old"}, LifecycleDeprecated})
+	defer SetStrictCodes(StrictOff)
+
+	if err := WrapCode(io.EOF, "test.lifecycle.retired"); HasCode(err, RetiredCode) {
+		t.Errorf("StrictOff: got %v, want no %s", err, RetiredCode)
+	}
+
+	SetStrictCodes(StrictReplace)
+	err := WrapCode(io.EOF, "test.lifecycle.retired")
+	if got, want := err.Error(), "test.lifecycle.retired - gone: retired_code - code test.lifecycle.retired is retired: EOF"; got != want {
+		t.Errorf("StrictReplace: got %q, want %q", got, want)
+	}
+	if got := Params(err.(*Error).Cause())["code"]; got != "test.lifecycle.retired" {
+		t.Errorf("StrictReplace: got code param %v", got)
+	}
+	if err := NewCode("test.lifecycle.deprecated"); HasCode(err, RetiredCode) {
+		t.Errorf("StrictReplace: deprecated code replaced: %v", err)
+	}
+
+	SetStrictCodes(StrictPanic)
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(r.(string), "is retired") {
+				t.Errorf("StrictPanic: got %v, want a retired code panic", r)
+			}
+		}()
+		NewCode("test.lifecycle.retired")
+	}()
+}
+
+func TestWriteCodeDocsDeprecated(t *testing.T) {
+	var buf bytes.Buffer
+	withRegistry(func() {
+		if err := WriteCodeDocs(&buf, "codes"); err != nil {
+			t.Fatalf("WriteCodeDocs: %v", err)
+		}
+	},
+		lifecycleCoder{testCoder{code: "user.legacy", status: 400}, LifecycleDeprecated},
+		lifecycleCoder{testCoder{code: "user.removed", status: 400}, LifecycleRetired},
+		testCoder{code: "user.current", status: 400},
+	)
+
+	out := buf.String()
+	for _, want := range []string{
+		"// Deprecated: the code is deprecated.\nconst UserLegacy",
+		"// Deprecated: the code is retired.\nconst UserRemoved",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteCodeDocs: missing %q in\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "Deprecated:") != 2 {
+		t.Errorf("WriteCodeDocs: want 2 deprecation markers in\n%s", out)
+	}
+}
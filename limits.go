@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// SerializationLimits bounds the size of errors serialized by ToJSON,
+// WriteHTTP and the other response writers of this package. Values that
+// exceed a limit are truncated and marked with "...". A zero limit means
+// no limit.
+type SerializationLimits struct {
+	// MaxMessageLen is the maximum length of a message in bytes.
+	MaxMessageLen int
+
+	// MaxParams is the maximum number of params of an error. Params beyond
+	// the limit are dropped in key order and their number is recorded in
+	// the "..." param.
+	MaxParams int
+
+	// MaxParamLen is the maximum length in bytes of a param value. Longer
+	// values are replaced by their truncated string form.
+	MaxParamLen int
+
+	// MaxDepth is the maximum number of errors of a chain that are encoded.
+	MaxDepth int
+}
+
+var limits SerializationLimits
+var limitsMux = &sync.RWMutex{}
+
+// SetSerializationLimits sets the limits applied when serializing errors.
+func SetSerializationLimits(l SerializationLimits) {
+	limitsMux.Lock()
+	defer limitsMux.Unlock()
+
+	limits = l
+}
+
+func serializationLimits() SerializationLimits {
+	limitsMux.RLock()
+	defer limitsMux.RUnlock()
+
+	return limits
+}
+
+// truncate shortens s to at most max bytes including the "..." marker.
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	if max <= len("...") {
+		return "..."[:max]
+	}
+
+	// Cut at a rune boundary so that the result stays valid UTF-8.
+	i := max - len("...")
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+
+	return s[:i] + "..."
+}
+
+// limitMessage applies the message length limit to msg.
+func (l SerializationLimits) limitMessage(msg string) string {
+	return truncate(msg, l.MaxMessageLen)
+}
+
+// limitParams applies the params limits to params. The params are copied
+// only if a limit is exceeded.
+func (l SerializationLimits) limitParams(params map[string]interface{}) map[string]interface{} {
+	if l.MaxParams <= 0 && l.MaxParamLen <= 0 || len(params) == 0 {
+		return params
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	dropped := 0
+	if l.MaxParams > 0 && len(keys) > l.MaxParams {
+		dropped = len(keys) - l.MaxParams
+		keys = keys[:l.MaxParams]
+	}
+
+	limited := make(map[string]interface{}, len(keys)+1)
+	for _, k := range keys {
+		v := params[k]
+		if l.MaxParamLen > 0 {
+			switch s := v.(type) {
+			case string:
+				v = truncate(s, l.MaxParamLen)
+			case bool, int, int64, float64, nil:
+			default:
+				if s := fmt.Sprint(v); len(s) > l.MaxParamLen {
+					v = truncate(s, l.MaxParamLen)
+				}
+			}
+		}
+		limited[k] = v
+	}
+	if dropped > 0 {
+		limited["..."] = dropped
+	}
+
+	return limited
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"hello", 0, "hello"},
+		{"hello", 5, "hello"},
+		{"hello world", 8, "hello..."},
+		{"hello", 2, ".."},
+		{"héllo wörld", 5, "h..."},
+		{"日本語テキスト", 10, "日本..."},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d): got %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestLimitParams(t *testing.T) {
+	l := SerializationLimits{MaxParams: 2, MaxParamLen: 6}
+	params := map[string]interface{}{"a": "abcdefghij", "b": 12345678, "c": []int{1, 2, 3, 4}}
+
+	want := map[string]interface{}{"a": "abc...", "b": 12345678, "...": 1}
+	if got := l.limitParams(params); !reflect.DeepEqual(got, want) {
+		t.Errorf("limitParams: got %v, want %v", got, want)
+	}
+	if got := (SerializationLimits{}).limitParams(params); !reflect.DeepEqual(got, params) {
+		t.Errorf("limitParams without limits: got %v, want %v", got, params)
+	}
+}
+
+func TestSerializationLimits(t *testing.T) {
+	SetSerializationLimits(SerializationLimits{MaxMessageLen: 10, MaxDepth: 2})
+	defer SetSerializationLimits(SerializationLimits{})
+
+	err := WrapCode(WithMessage(New("root"), "middle"), "test.limits", strings.Repeat("x", 100))
+
+	data, perr := ToJSON(err)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	if got, want := string(data), `{"schema_version":1,"chain":[{"code":"test.limits","message":"xxxxxxx..."},{"message":"middle"},{"message":"..."}]}`; got != want {
+		t.Errorf("ToJSON:\n got %s\n want %s", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, err)
+	if got, want := rec.Body.String(), `{"code":"test.limits","message":"xxxxxxx..."}`+"\n"; got != want {
+		t.Errorf("WriteHTTP: got %s, want %s", got, want)
+	}
+}
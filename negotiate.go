@@ -0,0 +1,223 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder writes the body of an error response in a media type, see
+// RegisterEncoder. The status and headers have already been written when
+// Encode is called.
+type Encoder interface {
+	Encode(w io.Writer, err error) error
+}
+
+// EncoderFunc is an adapter to use an ordinary function as an Encoder.
+type EncoderFunc func(w io.Writer, err error) error
+
+// Encode calls f(w, err).
+func (f EncoderFunc) Encode(w io.Writer, err error) error { return f(w, err) }
+
+type registeredEncoder struct {
+	mediaType   string
+	contentType string
+	enc         Encoder
+}
+
+// jsonContentType is the content type of the body of WriteHTTP, which is
+// also written when no registered encoder is accepted.
+const jsonContentType = "application/json; charset=utf-8"
+
+// JSONEncoder, ProblemEncoder and XMLEncoder are the Encoders registered by
+// default for application/json, application/problem+json and
+// application/xml and text/xml.
+var (
+	JSONEncoder Encoder = EncoderFunc(func(w io.Writer, err error) error {
+		_, body := httpResponse(err)
+		return json.NewEncoder(w).Encode(&body)
+	})
+
+	ProblemEncoder Encoder = EncoderFunc(func(w io.Writer, err error) error {
+		return json.NewEncoder(w).Encode(ToProblem(err))
+	})
+
+	XMLEncoder Encoder = EncoderFunc(func(w io.Writer, err error) error {
+		_, body := httpResponse(err)
+		if _, werr := io.WriteString(w, xml.Header); werr != nil {
+			return werr
+		}
+		return xml.NewEncoder(w).Encode(newXMLBody(body))
+	})
+)
+
+var encoders = []registeredEncoder{
+	{"application/json", jsonContentType, JSONEncoder},
+	{"application/problem+json", "application/problem+json", ProblemEncoder},
+	{"application/xml", "application/xml; charset=utf-8", XMLEncoder},
+	{"text/xml", "text/xml; charset=utf-8", XMLEncoder},
+}
+var encoderMux = &sync.RWMutex{}
+
+// RegisterEncoder registers enc as the Encoder of error responses in the
+// media type of contentType, such as "application/vnd.api+json", for
+// WriteHTTPLocalized and Handler, which choose the Encoder according to the
+// Accept header of the request. contentType is sent as the Content-Type of
+// the responses; its parameters are ignored when matching the Accept header.
+// Registering a media type again replaces its Encoder; a nil Encoder removes
+// it. Media types accepted equally are preferred in registration order,
+// starting with application/json.
+func RegisterEncoder(contentType string, enc Encoder) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		panic(fmt.Sprintf("errors: invalid content type %q: %v", contentType, err))
+	}
+
+	encoderMux.Lock()
+	defer encoderMux.Unlock()
+
+	for i, e := range encoders {
+		if e.mediaType == mediaType {
+			if enc == nil {
+				encoders = append(encoders[:i:i], encoders[i+1:]...)
+			} else {
+				encoders[i] = registeredEncoder{mediaType, contentType, enc}
+			}
+			return
+		}
+	}
+
+	if enc != nil {
+		encoders = append(encoders, registeredEncoder{mediaType, contentType, enc})
+	}
+}
+
+// mediaRange is a media range of an Accept header.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept returns the media ranges of an Accept header value, most
+// specific first, so that the first range matching a media type is the one
+// that applies to it.
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		typ := strings.ToLower(strings.TrimSpace(fields[0]))
+		i := strings.IndexByte(typ, '/')
+		if i <= 0 {
+			continue
+		}
+
+		r := mediaRange{typ: typ[:i], subtype: typ[i+1:], q: 1}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+
+	return ranges
+}
+
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	}
+
+	return 2
+}
+
+// quality returns the quality the ranges give mediaType.
+func quality(ranges []mediaRange, mediaType string) float64 {
+	i := strings.IndexByte(mediaType, '/')
+	typ, subtype := mediaType[:i], mediaType[i+1:]
+	for _, r := range ranges {
+		if r.typ == "*" || r.typ == typ && (r.subtype == "*" || r.subtype == subtype) {
+			return r.q
+		}
+	}
+
+	return 0
+}
+
+// negotiate returns the content type and Encoder of the registered media
+// type the Accept header value prefers. If the header is empty or accepts
+// none of them, JSON is used, since an error response is better than none.
+func negotiate(accept string) (string, Encoder) {
+	if strings.TrimSpace(accept) == "" {
+		return jsonContentType, JSONEncoder
+	}
+
+	ranges := parseAccept(accept)
+
+	encoderMux.RLock()
+	defer encoderMux.RUnlock()
+
+	best, bestQ := -1, 0.0
+	for i, e := range encoders {
+		if q := quality(ranges, e.mediaType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return jsonContentType, JSONEncoder
+	}
+
+	return encoders[best].contentType, encoders[best].enc
+}
+
+// xmlBody is the XML body written by XMLEncoder.
+type xmlBody struct {
+	XMLName   xml.Name       `xml:"error"`
+	Code      string         `xml:"code,omitempty"`
+	NumCode   *int           `xml:"num_code,omitempty"`
+	Message   string         `xml:"message"`
+	Params    []xmlBodyParam `xml:"params>param,omitempty"`
+	Action    string         `xml:"action,omitempty"`
+	Reference string         `xml:"reference,omitempty"`
+	ID        string         `xml:"id,omitempty"`
+}
+
+type xmlBodyParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+func newXMLBody(body httpBody) xmlBody {
+	x := xmlBody{
+		Code:      body.Code,
+		NumCode:   body.NumCode,
+		Message:   body.Message,
+		Action:    body.Action,
+		Reference: body.Reference,
+		ID:        body.ID,
+	}
+	for k, v := range body.Params {
+		x.Params = append(x.Params, xmlBodyParam{Name: k, Value: fmt.Sprint(v)})
+	}
+	sort.Slice(x.Params, func(i, j int) bool {
+		return x.Params[i].Name < x.Params[j].Name
+	})
+
+	return x
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", jsonContentType},
+		{"*/*", jsonContentType},
+		{"text/html", jsonContentType},
+		{"application/xml", "application/xml; charset=utf-8"},
+		{"text/xml, */*;q=0.1", "text/xml; charset=utf-8"},
+		{"application/json;q=0.5, application/problem+json", "application/problem+json"},
+		{"application/*;q=0.8, application/xml;q=0.9", "application/xml; charset=utf-8"},
+		{"application/*, application/json;q=0", "application/problem+json"},
+		{"APPLICATION/XML", "application/xml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		if got, _ := negotiate(tt.accept); got != tt.want {
+			t.Errorf("negotiate(%q): got %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestWriteHTTPNegotiated(t *testing.T) {
+	Register(problemCoder{testCoder{code: "test.negotiate", status: http.StatusNotFound, message: "not found"}, "https://docs.example.com/not-found"})
+	SetTranslator(mapTranslator{"de/test.negotiate": "nicht gefunden"})
+	defer SetTranslator(nil)
+
+	err := NewCodeWithParams("test.negotiate", map[string]interface{}{"id": 7, "kind": "a<b"})
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"application/json", `{"code":"test.negotiate","message":"nicht gefunden","params":{"id":7,"kind":"a\u003cb"},"reference":"https://docs.example.com/not-found"}` + "\n"},
+		{"application/problem+json", `{"code":"test.negotiate","id":7,"kind":"a\u003cb","status":404,"title":"nicht gefunden","type":"https://docs.example.com/not-found"}` + "\n"},
+		{"application/xml", xmlHeader + `<error><code>test.negotiate</code><message>nicht gefunden</message><params><param name="id">7</param><param name="kind">a&lt;b</param></params><reference>https://docs.example.com/not-found</reference></error>`},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", tt.accept)
+		r.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+		WriteHTTPLocalized(rec, r, Wrap(err, "lookup"))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status: got %d, want %d", tt.accept, rec.Code, http.StatusNotFound)
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.accept) {
+			t.Errorf("%s: Content-Type: got %q", tt.accept, got)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("%s: body:\ngot  %s\nwant %s", tt.accept, got, tt.want)
+		}
+	}
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+func TestRegisterEncoder(t *testing.T) {
+	defer RegisterEncoder("text/plain", nil)
+	RegisterEncoder("text/plain; charset=utf-8", EncoderFunc(func(w io.Writer, err error) error {
+		_, werr := fmt.Fprintf(w, "%s: %s\n", Code(err), Message(err))
+		return werr
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	WriteHTTPLocalized(rec, r, NewCode("test.negotiate.plain", "plain text"))
+
+	if got, want := rec.Body.String(), "test.negotiate.plain: plain text\n"; got != want {
+		t.Errorf("body: got %q, want %q", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type: got %q", got)
+	}
+
+	RegisterEncoder("text/plain", nil)
+	if got, _ := negotiate("text/plain"); got != jsonContentType {
+		t.Errorf("negotiate after removal: got %q", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterEncoder: invalid content type did not panic")
+		}
+	}()
+	RegisterEncoder("not a type", JSONEncoder)
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithoutCauseInError returns err with an Error text that ends with the
+// outermost coded error, "code - message", leaving out the causes it wraps.
+// This keeps sensitive or redundant cause text out of logs and responses
+// built from Error, while %+v still prints the whole chain with stack
+// traces, and Cause, Unwrap and the helpers of this package still see it.
+// If err has no coded error, its Error text is unchanged.
+// If err is nil, WithoutCauseInError returns nil.
+func WithoutCauseInError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withoutCause{err}
+}
+
+type withoutCause struct {
+	error
+}
+
+func (w *withoutCause) Error() string { return renderChain(w.error, true) }
+
+func (w *withoutCause) Cause() error { return w.error }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withoutCause) Unwrap() error { return w.error }
+
+func (w *withoutCause) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.error)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWithoutCauseInError(t *testing.T) {
+	inner := WrapCode(New("password=hunter2"), "test.nocause", "login failed")
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{WithoutCauseInError(inner), "test.nocause - login failed"},
+		{WithoutCauseInError(Wrap(inner, "handler")), "handler: test.nocause - login failed"},
+		{Wrap(WithoutCauseInError(inner), "outer"), "outer: test.nocause - login failed"},
+		{WithoutCauseInError(io.EOF), "EOF"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := fmt.Sprintf("%q", tt.err); got != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("test %d: %%q: got %s, want %q", i+1, got, tt.want)
+		}
+	}
+
+	err := WithoutCauseInError(inner)
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "password=hunter2") {
+		t.Errorf("%%+v: got %q, want the cause included", got)
+	}
+	if Code(err) != "test.nocause" || FirstCode(err) != "test.nocause" {
+		t.Errorf("Code, FirstCode: code test.nocause not found")
+	}
+	if WithoutCauseInError(nil) != nil {
+		t.Errorf("WithoutCauseInError(nil): got non-nil")
+	}
+}
+
+func TestWithoutCauseInErrorWire(t *testing.T) {
+	inner := WrapCodeWithParams(New("password=hunter2"), "test.nocause", map[string]interface{}{"user": "bob"}, "login failed")
+
+	for _, err := range []error{
+		WithoutCauseInError(inner),
+		Truncate(WithoutCauseInError(Wrap(inner, "handler")), 2),
+		ReplaceCause(WithoutCauseInError(inner), New("redacted")),
+	} {
+		data, perr := ToJSON(err)
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "redacted") {
+			t.Errorf("ToJSON: got %s, want the causes left out", data)
+		}
+
+		decoded, perr := FromJSON(data)
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		if got, want := decoded.Error(), err.Error(); got != want {
+			t.Errorf("FromJSON: got %q, want %q", got, want)
+		}
+		if Code(decoded) != "test.nocause" || Params(decoded)["user"] != "bob" {
+			t.Errorf("FromJSON: got code %q and params %v, want test.nocause and the user param", Code(decoded), Params(decoded))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import "sync/atomic"
+
+// NumCoder is implemented by Coders that also have a numeric code, for
+// legacy systems that require integer error codes. The registry maps
+// numeric codes to codes, see GetCoderByNum.
+type NumCoder interface {
+	NumCode() int
+}
+
+// numCodes maps numeric codes to codes. It is guarded by codeMux.
+var numCodes = map[int]string{}
+
+// register adds coder to the registry, replacing the Coder of the same
+// code, and keeps the numeric code mapping in sync. It is called with
+// codeMux held.
+func register(coder Coder) {
+	if frozenRegistry() != nil {
+		panic("errors: code " + coder.Code() + " registered after FreezeRegistry")
+	}
+
+	if old, ok := codes[coder.Code()].(NumCoder); ok && numCodes[old.NumCode()] == coder.Code() {
+		delete(numCodes, old.NumCode())
+	}
+
+	codes[coder.Code()] = coder
+	if n, ok := coder.(NumCoder); ok {
+		numCodes[n.NumCode()] = coder.Code()
+	}
+	atomic.AddUint64(&registryGeneration, 1)
+}
+
+// GetCoderByNum returns the registered Coder with the numeric code n, or nil
+// if there is none.
+func GetCoderByNum(n int) Coder {
+	if f := frozenRegistry(); f != nil {
+		return f.lookupNum(n)
+	}
+
+	codeMux.RLock()
+	defer codeMux.RUnlock()
+
+	if code, ok := numCodes[n]; ok {
+		return codes[code]
+	}
+
+	return nil
+}
+
+// NumCode returns the numeric code of the Coder of the outermost coded error
+// in err's chain, and reports whether it has one.
+func NumCode(err error) (int, bool) {
+	if wc := codeOf(err); wc != nil {
+		return numCode(wc)
+	}
+
+	return 0, false
+}
+
+func numCode(wc *Error) (int, bool) {
+	if n, ok := wc.Coder().(NumCoder); ok {
+		return n.NumCode(), true
+	}
+
+	return 0, false
+}
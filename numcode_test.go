@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type numCoder struct {
+	testCoder
+	num int
+}
+
+func (c numCoder) NumCode() int { return c.num }
+
+func TestGetCoderByNum(t *testing.T) {
+	Register(numCoder{testCoder{code: "test.num.a", status: http.StatusBadRequest, message: "bad"}, 91001})
+
+	if got := GetCoderByNum(91001); got == nil || got.Code() != "test.num.a" {
+		t.Errorf("GetCoderByNum(91001): got %v, want test.num.a", got)
+	}
+	if n, ok := NumCode(Wrap(NewCode("test.num.a"), "call")); !ok || n != 91001 {
+		t.Errorf("NumCode: got %d, %v, want 91001, true", n, ok)
+	}
+
+	Register(numCoder{testCoder{code: "test.num.a"}, 91002})
+	if got := GetCoderByNum(91001); got != nil {
+		t.Errorf("GetCoderByNum(91001) after renumbering: got %v, want nil", got)
+	}
+	if got := GetCoderByNum(91002); got == nil || got.Code() != "test.num.a" {
+		t.Errorf("GetCoderByNum(91002): got %v, want test.num.a", got)
+	}
+	if _, ok := NumCode(NewCode("test.num.unregistered")); ok {
+		t.Errorf("NumCode(unregistered): got ok")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustRegister with a used numeric code: got no panic")
+		}
+	}()
+	MustRegister(numCoder{testCoder{code: "test.num.b"}, 91002})
+}
+
+func TestNumCodeSerialization(t *testing.T) {
+	Register(numCoder{testCoder{code: "test.num.http", status: http.StatusConflict, message: "conflict"}, 91010})
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, NewCode("test.num.http"))
+	if got, want := w.Body.String(), `{"code":"test.num.http","num_code":91010,"message":"conflict"}`+"\n"; got != want {
+		t.Errorf("WriteHTTP: got %s, want %s", got, want)
+	}
+
+	if got := ToGraphQL(NewCode("test.num.http")).Extensions["num_code"]; got != 91010 {
+		t.Errorf("ToGraphQL: got num_code %v, want 91010", got)
+	}
+
+	err := FromProblem([]byte(`{"type":"about:blank","title":"conflict","num_code":91010}`))
+	if got := Code(err); got != "test.num.http" {
+		t.Errorf("FromProblem: got code %q, want %q", got, "test.num.http")
+	}
+
+	data, _ := ToJSON(Wrap(NewCode("test.num.http"), "call"))
+	if got, want := string(data), `{"schema_version":1,"chain":[{"message":"call"},{"code":"test.num.http","num_code":91010,"message":"conflict"}]}`; got != want {
+		t.Errorf("ToJSON: got %s, want %s", got, want)
+	}
+
+	// A numeric code of zero is kept.
+	Register(numCoder{testCoder{code: "test.num.zero", status: http.StatusConflict, message: "zero"}, 0})
+
+	w = httptest.NewRecorder()
+	WriteHTTP(w, NewCode("test.num.zero"))
+	if got, want := w.Body.String(), `{"code":"test.num.zero","num_code":0,"message":"zero"}`+"\n"; got != want {
+		t.Errorf("WriteHTTP: got %s, want %s", got, want)
+	}
+	if got, ok := ToProblem(NewCode("test.num.zero")).Extensions["num_code"]; !ok || got != 0 {
+		t.Errorf("ToProblem: got num_code %v, want 0", got)
+	}
+	data, _ = ToJSON(NewCode("test.num.zero"))
+	if !strings.Contains(string(data), `"num_code":0`) {
+		t.Errorf("ToJSON: got %s, want num_code 0", data)
+	}
+}
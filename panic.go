@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FromPanic converts the value v returned by recover into an error with the
+// supplied code and message. If v is an error it becomes the cause,
+// otherwise the cause is an error with the text "panic: v".
+// FromPanic must be called from the deferred function that recovered, while
+// the stack of the panicking goroutine is still intact:
+//
+//     defer func() {
+//             if r := recover(); r != nil {
+//                     err = errors.FromPanic(r, "worker.panic")
+//             }
+//     }()
+//
+// The stack trace of the panic, where the bug usually is, is recorded
+// separately from the stack trace of the recovery site, and %+v prints both
+// in labeled sections.
+// If v is nil, FromPanic returns nil.
+func FromPanic(v interface{}, code string, msgs ...string) error {
+	if v == nil {
+		return nil
+	}
+
+	cause, ok := v.(error)
+	if !ok {
+		cause = &fundamental{msg: fmt.Sprintf("panic: %v", v), stack: &stack{}}
+	}
+
+	const depth = 64
+	var pcs [depth]uintptr
+	n := providerCallers(1, pcs[:])
+	recovered, panicked := splitPanic(pcs[:n])
+
+	cause = checkCode(code, cause)
+
+	return &Error{
+		code:     code,
+		message:  explicitMessage(msgs),
+		source:   messageSourceOf(msgs),
+		cause:    cause,
+		id:       errorID(cause),
+		panicked: panicked,
+		seq:      recordWrap(code),
+		stack:    recovered,
+	}
+}
+
+// splitPanic splits the stack of a deferred function running because of a
+// panic into the frames of the recovery site and of the panic. The runtime
+// frames raising the panic are dropped. If the goroutine is not panicking,
+// the whole stack is the recovery site and the panic stack is nil.
+func splitPanic(pcs []uintptr) (*stack, *stack) {
+	for i, pc := range pcs {
+		if Frame(pc).name() != "runtime.gopanic" {
+			continue
+		}
+
+		recovered := append(stack(nil), pcs[:i]...)
+		rest := pcs[i+1:]
+		for len(rest) > 0 && strings.HasPrefix(Frame(rest[0]).name(), "runtime.") {
+			rest = rest[1:]
+		}
+		panicked := append(stack(nil), rest...)
+
+		return &recovered, &panicked
+	}
+
+	all := append(stack(nil), pcs...)
+	return &all, nil
+}
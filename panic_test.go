@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func panicking(v interface{}) {
+	panic(v)
+}
+
+func recoverPanic(v interface{}) (err error) {
+	defer func() {
+		err = FromPanic(recover(), "test.panic", "worker crashed")
+	}()
+
+	if v != nil {
+		panicking(v)
+	}
+
+	return nil
+}
+
+func TestFromPanic(t *testing.T) {
+	if err := recoverPanic(nil); err != nil {
+		t.Errorf("FromPanic(nil): got %v, want nil", err)
+	}
+
+	err := recoverPanic("boom")
+	if got, want := err.Error(), "test.panic - worker crashed: panic: boom"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+
+	err = recoverPanic(io.EOF)
+	if got := Cause(err); got != io.EOF {
+		t.Errorf("Cause(): got %v, want %v", got, io.EOF)
+	}
+
+	want := "(?s)^EOF\n" +
+		"test.panic - worker crashed\n" +
+		"panicked at:\n" +
+		"github.com/pkg/errors.panicking\n" +
+		"\t.+/github.com/pkg/errors/panic_test.go:11\n" +
+		"github.com/pkg/errors.recoverPanic\n" +
+		"\t.+/github.com/pkg/errors/panic_test.go:20\n" +
+		".*\n" +
+		"recovered at:\n" +
+		"github.com/pkg/errors.recoverPanic.func1\n" +
+		"\t.+/github.com/pkg/errors/panic_test.go:16$"
+	if got := fmt.Sprintf("%+v", err); !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("%%+v: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFromPanicNotPanicking(t *testing.T) {
+	err := FromPanic("value", "test.panic")
+	if got := fmt.Sprintf("%+v", err); regexp.MustCompile("panicked at").MatchString(got) {
+		t.Errorf("%%+v: got %q, want no panic section", got)
+	}
+}
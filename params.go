@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ParamsFromStruct converts the exported fields of the struct v, or of the
+// struct v points to, into a params map, so that domain structs can be
+// attached to errors without building maps by hand. The key of a field is
+// the name in its `errparam` tag, or else the field name. Fields tagged
+// with "-" are skipped, and fields whose tag has the omitempty option are
+// skipped if they hold the zero value:
+//
+//     type QuotaParams struct {
+//             Limit  int    `errparam:"limit"`
+//             Bucket string `errparam:"bucket,omitempty"`
+//             Token  string `errparam:"-"`
+//     }
+//
+// Maps with string keys are copied as they are. Other values result in nil
+// params.
+func ParamsFromStruct(v interface{}) map[string]interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		params := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			params[iter.Key().String()] = iter.Value().Interface()
+		}
+		return params
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	rt := rv.Type()
+	params := make(map[string]interface{}, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("errparam")
+		name, opts := tag, ""
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			name, opts = tag[:comma], tag[comma:]
+		}
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value := rv.Field(i)
+		if strings.Contains(opts, ",omitempty") && value.IsZero() {
+			continue
+		}
+		params[name] = value.Interface()
+	}
+
+	return params
+}
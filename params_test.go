@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParamsFromStruct(t *testing.T) {
+	type quota struct {
+		Limit  int    `errparam:"limit"`
+		Bucket string `errparam:"bucket,omitempty"`
+		Owner  string `errparam:"owner,omitempty" json:"user"`
+		Token  string `errparam:"-"`
+		Dash   string `errparam:"-,"`
+		Plain  bool
+		hidden int
+	}
+
+	tests := []struct {
+		v    interface{}
+		want map[string]interface{}
+	}{
+		{nil, nil},
+		{42, nil},
+		{(*quota)(nil), nil},
+		{map[int]string{1: "a"}, nil},
+		{map[string]int{"a": 1}, map[string]interface{}{"a": 1}},
+		{quota{Limit: 10, Token: "t"}, map[string]interface{}{"limit": 10, "-": "", "Plain": false}},
+		{&quota{Bucket: "b", Owner: "o", Plain: true}, map[string]interface{}{"limit": 0, "bucket": "b", "owner": "o", "-": "", "Plain": true}},
+	}
+
+	for i, tt := range tests {
+		if got := ParamsFromStruct(tt.v); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: ParamsFromStruct(%#v): got %v, want %v", i+1, tt.v, got, tt.want)
+		}
+	}
+}
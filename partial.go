@@ -0,0 +1,145 @@
+// +build go1.18
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Partial is the result of a bulk operation in which each item succeeds or
+// fails on its own. Items and errors are keyed by the index of the item in
+// the request. A Partial is safe for concurrent use.
+//
+// Partial marshals to a JSON array of per-item results, ordered by index,
+// suitable for a 207 Multi-Status response:
+//
+//     [{"index":0,"status":200,"item":{...}},
+//      {"index":1,"status":404,"error":{"code":"user.notfound","message":"..."}}]
+type Partial[T any] struct {
+	mu    sync.Mutex
+	items map[int]T
+	errs  map[int]error
+}
+
+// NewPartial returns an empty Partial.
+func NewPartial[T any]() *Partial[T] {
+	return &Partial[T]{
+		items: map[int]T{},
+		errs:  map[int]error{},
+	}
+}
+
+// Succeed records item as the result of the item at index.
+func (p *Partial[T]) Succeed(index int, item T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.errs, index)
+	p.items[index] = item
+}
+
+// Fail records err as the result of the item at index.
+// A nil err is ignored.
+func (p *Partial[T]) Fail(index int, err error) {
+	if err == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.items, index)
+	p.errs[index] = err
+}
+
+// Items returns the successful items by index.
+func (p *Partial[T]) Items() map[int]T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	items := make(map[int]T, len(p.items))
+	for i, item := range p.items {
+		items[i] = item
+	}
+
+	return items
+}
+
+// Errors returns the errors of the failed items by index.
+func (p *Partial[T]) Errors() map[int]error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := make(map[int]error, len(p.errs))
+	for i, err := range p.errs {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Err returns an Aggregate of the errors of the failed items, ordered by
+// index, or nil if no item failed.
+func (p *Partial[T]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := make([]error, 0, len(p.errs))
+	for _, i := range sortedKeys(p.errs) {
+		errs = append(errs, p.errs[i])
+	}
+
+	return NewAggregate(errs)
+}
+
+// StatusCode returns 207 Multi-Status if any item failed and 200 OK otherwise.
+func (p *Partial[T]) StatusCode() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.errs) > 0 {
+		return http.StatusMultiStatus
+	}
+
+	return http.StatusOK
+}
+
+type partialResult struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Item   interface{} `json:"item,omitempty"`
+	Error  *httpBody   `json:"error,omitempty"`
+}
+
+func (p *Partial[T]) MarshalJSON() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	results := make([]partialResult, 0, len(p.items)+len(p.errs))
+	for i, item := range p.items {
+		results = append(results, partialResult{Index: i, Status: http.StatusOK, Item: item})
+	}
+	for i, err := range p.errs {
+		status, body := httpResponse(err)
+		results = append(results, partialResult{Index: i, Status: status, Error: &body})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+
+	return json.Marshal(results)
+}
+
+func sortedKeys(errs map[int]error) []int {
+	keys := make([]int, 0, len(errs))
+	for i := range errs {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,56 @@
+// +build go1.18
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestPartial(t *testing.T) {
+	Register(testCoder{code: "test.partial.notfound", status: http.StatusNotFound, message: "not found"})
+
+	p := NewPartial[string]()
+	p.Succeed(0, "alice")
+	p.Fail(2, New("internal"))
+	p.Fail(1, NewCode("test.partial.notfound"))
+	p.Fail(3, nil)
+
+	if got := p.StatusCode(); got != http.StatusMultiStatus {
+		t.Errorf("StatusCode(): got %d, want %d", got, http.StatusMultiStatus)
+	}
+	if got := len(p.Items()); got != 1 {
+		t.Errorf("Items(): got %d items, want 1", got)
+	}
+	if got := len(p.Errors()); got != 2 {
+		t.Errorf("Errors(): got %d errors, want 2", got)
+	}
+	if got, want := p.Err().Error(), "[test.partial.notfound - not found, internal]"; got != want {
+		t.Errorf("Err(): got %q, want %q", got, want)
+	}
+
+	got, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"index":0,"status":200,"item":"alice"},` +
+		`{"index":1,"status":404,"error":{"code":"test.partial.notfound","message":"not found"}},` +
+		`{"index":2,"status":500,"error":{"message":"Internal Server Error"}}]`
+	if string(got) != want {
+		t.Errorf("MarshalJSON:\n got %s\n want %s", got, want)
+	}
+}
+
+func TestPartialSuccess(t *testing.T) {
+	p := NewPartial[int]()
+	p.Fail(0, New("transient"))
+	p.Succeed(0, 42)
+
+	if got := p.StatusCode(); got != http.StatusOK {
+		t.Errorf("StatusCode(): got %d, want %d", got, http.StatusOK)
+	}
+	if err := p.Err(); err != nil {
+		t.Errorf("Err(): got %v, want nil", err)
+	}
+}
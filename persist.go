@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// persistedError is the file format of SaveError.
+type persistedError struct {
+	SavedAt time.Time          `json:"saved_at"`
+	Error   *Wire              `json:"error"`
+	Stacks  [][]persistedFrame `json:"stacks,omitempty"`
+}
+
+// persistedFrame is a frame of the stack trace of a persisted coded error.
+type persistedFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// SaveError writes err's chain, as encoded by ToJSON, and the stack traces
+// of its coded errors to the file at path, for batch jobs and command line
+// tools that report failures of a previous run after restarting:
+//
+//     if err := job.Run(); err != nil {
+//             errors.SaveError(filepath.Join(stateDir, "last-error.json"), err)
+//     }
+//
+// The file is replaced atomically and readable only by its owner. A nil
+// err is saved as well, so that a successful run clears a saved failure.
+func SaveError(path string, err error) error {
+	p := persistedError{SavedAt: time.Now().UTC(), Error: ToWire(err)}
+	walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok {
+			p.Stacks = append(p.Stacks, persistedStack(wc))
+		}
+
+		return true
+	})
+
+	data, merr := json.MarshalIndent(p, "", "  ")
+	if merr != nil {
+		return Wrap(merr, "errors: encode saved error")
+	}
+
+	f, ferr := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if ferr != nil {
+		return Wrap(ferr, "errors: save error")
+	}
+	defer os.Remove(f.Name())
+
+	if _, werr := f.Write(data); werr != nil {
+		f.Close()
+		return Wrap(werr, "errors: save error")
+	}
+	if cerr := f.Close(); cerr != nil {
+		return Wrap(cerr, "errors: save error")
+	}
+
+	return Wrap(os.Rename(f.Name(), path), "errors: save error")
+}
+
+// persistedStack returns the frames of the stack trace of wc, which are
+// its restored frames if wc was itself loaded by LoadError.
+func persistedStack(wc *Error) []persistedFrame {
+	if wc.restored != nil {
+		return wc.restored
+	}
+	if wc.stack == nil {
+		return nil
+	}
+
+	frames := make([]persistedFrame, len(*wc.stack))
+	for i, pc := range *wc.stack {
+		info := Frame(pc).resolve()
+		frames[i] = persistedFrame{Function: info.name, File: info.file, Line: info.line}
+	}
+
+	return frames
+}
+
+// LoadError reads an error saved by SaveError from the file at path. The
+// chain is decoded as by FromJSON, and the coded errors are given the stack
+// traces they had when they were saved, which are written when the error
+// is formatted with %+v; their StackTrace method returns the stack trace of
+// the LoadError call. The stack traces of other errors are not saved.
+// If a nil error was saved, LoadError returns nil.
+// The second result reports failures to read or decode the file.
+func LoadError(path string) (error, error) {
+	f, ferr := os.Open(path)
+	if ferr != nil {
+		return nil, Wrap(ferr, "errors: load error")
+	}
+	defer f.Close()
+
+	data, rerr := ioutil.ReadAll(io.LimitReader(f, MaxWireBytes+1))
+	if rerr != nil {
+		return nil, Wrap(rerr, "errors: load error")
+	}
+	if len(data) > MaxWireBytes {
+		return nil, Errorf("errors: saved error of more than %d bytes", MaxWireBytes)
+	}
+
+	var p persistedError
+	if jerr := json.Unmarshal(data, &p); jerr != nil {
+		return nil, Wrap(jerr, "errors: invalid saved error")
+	}
+
+	err, werr := FromWire(p.Error)
+	if werr != nil {
+		return nil, werr
+	}
+
+	i := 0
+	walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			if i < len(p.Stacks) {
+				e.restored = p.Stacks[i]
+				i++
+			}
+		case *fundamental:
+			e.stack = &stack{}
+		}
+
+		return true
+	})
+
+	return err, nil
+}
+
+// formatRestored writes the restored stack trace frames as a stack trace
+// is written with %+v.
+func formatRestored(s io.Writer, frames []persistedFrame) {
+	for _, f := range frames {
+		fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+}
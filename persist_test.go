@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func saveFailure() error {
+	return WrapCode(WrapCodeWithParams(io.EOF, "test.persist.read", map[string]interface{}{"offset": 42}, "read"), "test.persist.job", "job failed")
+}
+
+func TestSaveLoadError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "last-error.json")
+	err := saveFailure()
+
+	if serr := SaveError(path, err); serr != nil {
+		t.Fatalf("SaveError: %v", serr)
+	}
+	info, serr := os.Stat(path)
+	if serr != nil {
+		t.Fatalf("SaveError: %v", serr)
+	}
+	// Windows has no Unix permission bits.
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("SaveError: got mode %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, lerr := LoadError(path)
+	if lerr != nil {
+		t.Fatalf("LoadError: %v", lerr)
+	}
+	if got, want := loaded.Error(), err.Error(); got != want {
+		t.Errorf("LoadError: got %q, want %q", got, want)
+	}
+	if !HasCode(loaded, "test.persist.read") || Params(loaded.(*Error).Cause())["offset"] != 42 {
+		t.Errorf("LoadError: lost the inner coded error: %#v", loaded)
+	}
+
+	got := fmt.Sprintf("%+v", loaded)
+	if !strings.Contains(got, "errors.saveFailure\n\t") || !strings.Contains(got, "persist_test.go:") {
+		t.Errorf("%%+v: saved stack trace missing in %s", got)
+	}
+	if strings.Contains(got, "errors.LoadError") {
+		t.Errorf("%%+v: got the stack trace of LoadError in %s", got)
+	}
+
+	// Saving a loaded error keeps the original stack traces.
+	if serr := SaveError(path, Wrap(loaded, "resumed")); serr != nil {
+		t.Fatal(serr)
+	}
+	reloaded, _ := LoadError(path)
+	if got := fmt.Sprintf("%+v", reloaded); !strings.Contains(got, "errors.saveFailure\n\t") {
+		t.Errorf("%%+v after saving again: saved stack trace missing in %s", got)
+	}
+
+	if serr := SaveError(path, nil); serr != nil {
+		t.Fatal(serr)
+	}
+	if loaded, lerr := LoadError(path); loaded != nil || lerr != nil {
+		t.Errorf("LoadError of nil: got %v, %v", loaded, lerr)
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 0 {
+		t.Errorf("SaveError: left temporary files %v", matches)
+	}
+}
+
+func TestLoadErrorInvalid(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if _, lerr := LoadError(filepath.Join(dir, "missing.json")); !os.IsNotExist(Cause(lerr)) {
+		t.Errorf("missing file: got %v", lerr)
+	}
+
+	path := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(path, []byte("{"), 0600)
+	if _, lerr := LoadError(path); lerr == nil {
+		t.Errorf("invalid file: want an error")
+	}
+
+	if serr := SaveError(filepath.Join(dir, "missing", "error.json"), New("x")); serr == nil {
+		t.Errorf("SaveError into a missing directory: want an error")
+	}
+}
+
+// tempDir returns a new temporary directory, to be removed by the test.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "errors-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
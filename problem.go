@@ -0,0 +1,221 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is a problem details object as defined by RFC 7807, the
+// application/problem+json format.
+// Extensions are encoded as members of the problem object next to the
+// standard members, which take precedence over extensions of the same name.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// problemMembers are the standard members of a problem details object.
+var problemMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			m[k] = v
+		}
+	}
+
+	if p.Type != "" {
+		m["type"] = p.Type
+	}
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+
+	return json.Marshal(m)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	p.Type, _ = m["type"].(string)
+	p.Title, _ = m["title"].(string)
+	if status, ok := m["status"].(float64); ok {
+		p.Status = int(status)
+	}
+	p.Detail, _ = m["detail"].(string)
+	p.Instance, _ = m["instance"].(string)
+
+	for k, v := range m {
+		if problemMembers[k] {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = map[string]interface{}{}
+		}
+		p.Extensions[k] = v
+	}
+
+	return nil
+}
+
+// ToProblem converts err into a problem details object.
+// The outermost coded error in err's chain provides the members: the type is
+// the Reference of its registered Coder, or "about:blank" if there is none,
+// the title is its message and the status is the status code of the Coder.
+// The code, numeric code, action and ID are added as the "code",
+// "num_code", "action" and "id" extensions, and the params as extensions of
+// their own, unless their names are taken. Errors without a code, or with an
+// unregistered code, are converted to a 500 Internal Server Error problem;
+// the text of errors without a code is not exposed.
+func ToProblem(err error) Problem {
+	status, body := httpResponse(err)
+
+	p := Problem{
+		Type:   body.Reference,
+		Title:  body.Message,
+		Status: status,
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if body.Code == "" {
+		return p
+	}
+
+	p.Extensions = make(map[string]interface{}, len(body.Params)+3)
+	for k, v := range body.Params {
+		p.Extensions[k] = v
+	}
+	p.Extensions["code"] = body.Code
+	if body.NumCode != nil {
+		p.Extensions["num_code"] = *body.NumCode
+	}
+	if body.Action != "" {
+		p.Extensions["action"] = body.Action
+	}
+	if body.ID != "" {
+		p.Extensions["id"] = body.ID
+	}
+
+	return p
+}
+
+// WriteProblem writes err to w as an application/problem+json response, with
+// the problem details object of ToProblem. As with WriteHTTP, the delay
+// reported by RetryAfter is sent in the Retry-After header of 429 Too Many
+// Requests and 503 Service Unavailable responses.
+func WriteProblem(w http.ResponseWriter, err error) {
+	p := ToProblem(err)
+	setRetryAfter(w.Header(), p.Status, err)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// FromProblem converts an application/problem+json document, as received by
+// clients of RFC 7807 services, into an error.
+// The code is read from the "code" extension if it is registered, and
+// otherwise looked up by the "num_code" extension or by matching the problem
+// type against the Reference of the registered Coders. If none of them gives
+// a registered code, an unregistered "code" extension is kept as the code;
+// problems without one become errors without a code, with the title as text.
+// The title is the message, falling back to the message of the Coder, the
+// "id" extension is the instance ID and the other extensions are the params.
+// If body is not a problem details object, the returned error says so.
+func FromProblem(body []byte) error {
+	var p Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		return Wrap(err, "errors: invalid problem document")
+	}
+
+	code, _ := p.Extensions["code"].(string)
+	if n, ok := p.Extensions["num_code"].(float64); ok && GetCoder(code) == nil {
+		if coder := GetCoderByNum(int(n)); coder != nil {
+			code = coder.Code()
+		}
+	}
+	if GetCoder(code) == nil {
+		if found := codeByReference(p.Type); found != "" || code == "" {
+			code = found
+		}
+	}
+
+	if code == "" {
+		msg := p.Title
+		if msg == "" {
+			msg = http.StatusText(p.Status)
+		}
+
+		return &fundamental{
+			msg:   msg,
+			stack: callers(),
+		}
+	}
+
+	id, _ := p.Extensions["id"].(string)
+	var params map[string]interface{}
+	for k, v := range p.Extensions {
+		if k == "code" || k == "num_code" || k == "action" || k == "id" {
+			continue
+		}
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		params[k] = v
+	}
+
+	msg := p.Title
+	if msg == "" {
+		msg = registryMessage(code)
+	}
+
+	return &Error{
+		code:    code,
+		message: msg,
+		params:  params,
+		id:      id,
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// codeByReference returns the code of the registered Coder whose Reference
+// is ref, the first in sort order if several Coders share it, or the empty
+// string if there is none.
+func codeByReference(ref string) string {
+	if ref == "" || ref == "about:blank" {
+		return ""
+	}
+
+	codeMux.RLock()
+	defer codeMux.RUnlock()
+
+	found := ""
+	for code, coder := range codes {
+		if coder.Reference() == ref && (found == "" || code < found) {
+			found = code
+		}
+	}
+
+	return found
+}
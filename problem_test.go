@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type problemCoder struct {
+	testCoder
+	reference string
+}
+
+func (c problemCoder) Reference() string { return c.reference }
+
+func TestWriteProblem(t *testing.T) {
+	Register(problemCoder{testCoder{code: "test.problem.funds", status: http.StatusForbidden, message: "insufficient funds"}, "https://example.com/probs/out-of-credit"})
+	Register(testCoder{code: "test.problem.busy", status: http.StatusServiceUnavailable, message: "busy"})
+
+	tests := []struct {
+		err    error
+		status int
+		body   string
+		after  string
+	}{{
+		err:    Wrap(NewCodeWithParams("test.problem.funds", map[string]interface{}{"balance": 30, "title": "ignored"}), "charge"),
+		status: http.StatusForbidden,
+		body:   `{"balance":30,"code":"test.problem.funds","status":403,"title":"insufficient funds","type":"https://example.com/probs/out-of-credit"}` + "\n",
+	}, {
+		err:    WithRetryAfter(NewCode("test.problem.busy"), 2*time.Second),
+		status: http.StatusServiceUnavailable,
+		body:   `{"code":"test.problem.busy","status":503,"title":"busy","type":"about:blank"}` + "\n",
+		after:  "2",
+	}, {
+		err:    io.EOF,
+		status: http.StatusInternalServerError,
+		body:   `{"status":500,"title":"Internal Server Error","type":"about:blank"}` + "\n",
+	}}
+
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		WriteProblem(w, tt.err)
+
+		if w.Code != tt.status {
+			t.Errorf("test %d: status: got %d, want %d", i+1, w.Code, tt.status)
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("test %d: Content-Type: got %q, want %q", i+1, got, "application/problem+json")
+		}
+		if got := w.Body.String(); got != tt.body {
+			t.Errorf("test %d: body: got %s, want %s", i+1, got, tt.body)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.after {
+			t.Errorf("test %d: Retry-After: got %q, want %q", i+1, got, tt.after)
+		}
+	}
+}
+
+func TestFromProblem(t *testing.T) {
+	Register(problemCoder{testCoder{code: "test.problem.from", status: http.StatusForbidden, message: "insufficient funds"}, "https://example.com/probs/from"})
+
+	tests := []struct {
+		body   string
+		code   string
+		text   string
+		params map[string]interface{}
+	}{
+		{`{"type":"https://example.com/probs/from","title":"no money","status":403,"balance":30}`, "test.problem.from", "test.problem.from - no money", map[string]interface{}{"balance": 30.0}},
+		{`{"type":"about:blank","status":403,"code":"test.problem.from","id":"e-1"}`, "test.problem.from", "test.problem.from - insufficient funds", nil},
+		{`{"type":"about:blank","title":"teapot","code":"remote.teapot"}`, "remote.teapot", "remote.teapot - teapot", nil},
+		{`{"type":"https://example.com/probs/unknown","status":502}`, "", "Bad Gateway", nil},
+		{`<html>`, "", "errors: invalid problem document: invalid character '<' looking for beginning of value", nil},
+	}
+
+	for i, tt := range tests {
+		err := FromProblem([]byte(tt.body))
+		if got := Code(err); got != tt.code {
+			t.Errorf("test %d: Code: got %q, want %q", i+1, got, tt.code)
+		}
+		if got := err.Error(); got != tt.text {
+			t.Errorf("test %d: Error: got %q, want %q", i+1, got, tt.text)
+		}
+		if got := Params(err); len(got) != len(tt.params) || len(got) > 0 && got["balance"] != tt.params["balance"] {
+			t.Errorf("test %d: Params: got %v, want %v", i+1, got, tt.params)
+		}
+	}
+
+	if got := ErrorID(FromProblem([]byte(tests[1].body))); got != "e-1" {
+		t.Errorf("ErrorID: got %q, want %q", got, "e-1")
+	}
+}
+
+func TestFromProblemSharedReference(t *testing.T) {
+	for _, code := range []string{"test.problem.shared.c", "test.problem.shared.a", "test.problem.shared.b"} {
+		Register(problemCoder{testCoder{code: code, status: http.StatusConflict}, "https://example.com/probs/shared"})
+	}
+
+	for i := 0; i < 10; i++ {
+		err := FromProblem([]byte(`{"type":"https://example.com/probs/shared","status":409}`))
+		if got := Code(err); got != "test.problem.shared.a" {
+			t.Fatalf("Code: got %q, want %q", got, "test.problem.shared.a")
+		}
+	}
+}
+
+func TestProblemRoundTrip(t *testing.T) {
+	Register(problemCoder{testCoder{code: "test.problem.trip", status: http.StatusForbidden, message: "insufficient funds"}, "https://example.com/probs/trip"})
+
+	w := httptest.NewRecorder()
+	WriteProblem(w, NewCodeWithParams("test.problem.trip", map[string]interface{}{"balance": 30}))
+
+	err := FromProblem(w.Body.Bytes())
+	if got := Code(err); got != "test.problem.trip" {
+		t.Errorf("Code: got %q, want %q", got, "test.problem.trip")
+	}
+	if got := Params(err)["balance"]; got != 30.0 {
+		t.Errorf("Params: got %v, want 30", got)
+	}
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// RegistrySource provides Coders from outside the program, such as a
+// config service, so that messages and references can be changed at runtime.
+type RegistrySource interface {
+	Coders() ([]Coder, error)
+}
+
+// Reload registers the Coders of source, replacing the registered Coders of
+// the same codes. Codes that source does not provide stay registered, so
+// code identities remain stable across reloads.
+// The Coders are checked as RegisterAll does, except that they may replace
+// registered Coders: Reload fails if one is nil, has an empty or reserved
+// code, or takes the numeric code of another code.
+// If source fails, a Coder is invalid or the registry is frozen, the
+// registry is left unchanged.
+func Reload(source RegistrySource) error {
+	coders, err := source.Coders()
+	if err != nil {
+		return WithMessage(err, "errors: reload registry")
+	}
+
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	if frozenRegistry() != nil {
+		return New("errors: reload registry: registry is frozen")
+	}
+
+	var failures []error
+	batch := map[string]bool{}
+	batchNums := map[int]string{}
+	for i, coder := range coders {
+		if coder == nil {
+			failures = append(failures, Errorf("code: coder %d is nil", i))
+			continue
+		}
+
+		code := coder.Code()
+		switch {
+		case code == "":
+			failures = append(failures, Errorf("code: coder %d has an empty code", i))
+			continue
+		case batch[code]:
+			failures = append(failures, Errorf("code: %s is provided twice", code))
+			continue
+		}
+		batch[code] = true
+
+		if err := checkReservation(coder); err != nil {
+			failures = append(failures, err)
+		}
+		if n, ok := coder.(NumCoder); ok {
+			if other, ok := numCodes[n.NumCode()]; ok && other != code {
+				failures = append(failures, Errorf("code: numeric code %d of %s already used by %s", n.NumCode(), code, other))
+			} else if other, ok := batchNums[n.NumCode()]; ok {
+				failures = append(failures, Errorf("code: numeric code %d of %s already used by %s", n.NumCode(), code, other))
+			}
+			batchNums[n.NumCode()] = code
+		}
+	}
+	if len(failures) > 0 {
+		return WithMessage(NewAggregate(failures), "errors: reload registry")
+	}
+
+	for _, coder := range coders {
+		register(coder)
+	}
+
+	return nil
+}
+
+// Watch calls Reload with source every interval until ctx is done.
+// Reload errors are passed to onError, if it is not nil, and do not stop
+// the watch. Watch blocks; it is usually run in its own goroutine.
+func Watch(ctx context.Context, source RegistrySource, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := Reload(source); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type sourceFunc func() ([]Coder, error)
+
+func (f sourceFunc) Coders() ([]Coder, error) { return f() }
+
+func TestReload(t *testing.T) {
+	Register(testCoder{code: "test.reload", message: "old"})
+	Register(testCoder{code: "test.reload.kept", message: "kept"})
+
+	err := Reload(sourceFunc(func() ([]Coder, error) {
+		return []Coder{testCoder{code: "test.reload", message: "new"}}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := GetCoder("test.reload").Message(); got != "new" {
+		t.Errorf("Message after reload: got %q, want %q", got, "new")
+	}
+	if GetCoder("test.reload.kept") == nil {
+		t.Errorf("Reload: removed code not provided by source")
+	}
+
+	err = Reload(sourceFunc(func() ([]Coder, error) {
+		return nil, New("unreachable")
+	}))
+	if got, want := err.Error(), "errors: reload registry: unreachable"; got != want {
+		t.Errorf("Reload error: got %q, want %q", got, want)
+	}
+	if got := GetCoder("test.reload").Message(); got != "new" {
+		t.Errorf("Message after failed reload: got %q, want %q", got, "new")
+	}
+}
+
+func TestReloadInvalid(t *testing.T) {
+	Register(testCoder{code: "test.reload.invalid", message: "old"})
+
+	tests := [][]Coder{
+		{testCoder{code: "test.reload.invalid", message: "new"}, nil},
+		{testCoder{code: "test.reload.invalid", message: "new"}, testCoder{message: "empty"}},
+		{testCoder{code: "test.reload.invalid", message: "new"}, testCoder{code: "test.reload.invalid", message: "twice"}},
+	}
+	for i, coders := range tests {
+		err := Reload(sourceFunc(func() ([]Coder, error) { return coders, nil }))
+		if err == nil {
+			t.Errorf("%d: Reload: got nil error", i)
+		}
+		if got := GetCoder("test.reload.invalid").Message(); got != "old" {
+			t.Errorf("%d: Message after invalid reload: got %q, want %q", i, got, "old")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reloaded := make(chan struct{}, 1)
+	failed := make(chan error, 1)
+
+	calls := 0
+	source := sourceFunc(func() ([]Coder, error) {
+		calls++
+		if calls == 1 {
+			return nil, New("unreachable")
+		}
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+		return []Coder{testCoder{code: "test.watch", message: "watched"}}, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, source, time.Millisecond, func(err error) {
+			select {
+			case failed <- err:
+			default:
+			}
+		})
+		close(done)
+	}()
+
+	<-failed
+	<-reloaded
+	cancel()
+	<-done
+
+	if GetCoder("test.watch") == nil {
+		t.Errorf("Watch: code not registered")
+	}
+}
+
+func TestLazyMessages(t *testing.T) {
+	Register(testCoder{code: "test.lazy", message: "before"})
+
+	eager := NewCode("test.lazy")
+	unread := NewCode("test.lazy")
+	if got := Message(eager); got != "before" {
+		t.Errorf("Message before update: got %q, want %q", got, "before")
+	}
+	LazyMessages = true
+	lazy := NewCode("test.lazy")
+	explicit := NewCode("test.lazy", "explicit")
+	LazyMessages = false
+
+	Register(testCoder{code: "test.lazy", message: "after"})
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{eager, "before"},
+		{unread, "after"},
+		{lazy, "after"},
+		{explicit, "explicit"},
+	}
+
+	for i, tt := range tests {
+		if got := Message(tt.err); got != tt.want {
+			t.Errorf("test %d: Message: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+	if got, want := lazy.Error(), "test.lazy - after"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+}
+
+func TestNewCodeWithoutRegistryAccess(t *testing.T) {
+	Register(testCoder{code: "test.deferred", message: "registered"})
+
+	// Constructors must not wait for the registry lock.
+	codeMux.Lock()
+	done := make(chan error, 1)
+	go func() { done <- NewCode("test.deferred") }()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(time.Second):
+		codeMux.Unlock()
+		t.Fatal("NewCode blocked on the registry lock")
+	}
+	codeMux.Unlock()
+
+	if got := Message(err); got != "registered" {
+		t.Errorf("Message: got %q, want %q", got, "registered")
+	}
+}
@@ -0,0 +1,375 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reporter sends errors to an external service, such as an incident
+// tracker. Report must be safe for concurrent use; it is not called with a
+// nil error by the reporters of this package.
+type Reporter interface {
+	Report(ctx context.Context, err error)
+}
+
+// ReporterFunc adapts a function to a Reporter.
+type ReporterFunc func(ctx context.Context, err error)
+
+// Report calls f(ctx, err).
+func (f ReporterFunc) Report(ctx context.Context, err error) { f(ctx, err) }
+
+// FanOut returns a Reporter that reports each error to all of reporters,
+// in order.
+func FanOut(reporters ...Reporter) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		if err == nil {
+			return
+		}
+
+		for _, r := range reporters {
+			r.Report(ctx, err)
+		}
+	})
+}
+
+// Sample returns a Reporter that reports a random fraction rate of the
+// errors to r, with rate between 0 (none) and 1 (all).
+func Sample(r Reporter, rate float64) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		if err == nil || rate <= 0 {
+			return
+		}
+
+		if rate >= 1 || rand.Float64() < rate {
+			r.Report(ctx, err)
+		}
+	})
+}
+
+// AsyncReporter reports errors to another Reporter from background
+// goroutines, so that slow reporters do not delay the code reporting errors.
+// It is created with Async or NewAsyncReporter.
+type AsyncReporter struct {
+	dropped uint64 // accessed atomically; first for 64-bit alignment
+
+	r     Reporter
+	opts  AsyncOptions
+	queue chan report
+	wg    sync.WaitGroup
+	done  chan struct{}
+
+	// base is the parent of the contexts passed to r. It is canceled when
+	// Shutdown gives up waiting, so that reports in flight can stop.
+	base     context.Context
+	cancel   context.CancelFunc
+	abandon  int32 // accessed atomically; set when queued reports are dropped
+	shutdown sync.Once
+
+	// stopping is closed when Shutdown is called. Report holds mu for
+	// reading while it may add to senders, so that Shutdown can wait for
+	// the blocked senders before closing the queue.
+	stopping chan struct{}
+	stop     sync.Once
+	mu       sync.RWMutex
+	senders  sync.WaitGroup
+}
+
+type report struct {
+	ctx context.Context
+	err error
+}
+
+// AsyncOptions configures an AsyncReporter.
+type AsyncOptions struct {
+	// Workers is the number of goroutines reporting errors concurrently.
+	// It defaults to 1.
+	Workers int
+
+	// QueueSize is the number of errors queued for the workers. Errors
+	// reported while the queue is full are dropped and counted.
+	QueueSize int
+
+	// BlockTimeout is how long Report waits for room in a full queue
+	// before dropping the error, to apply backpressure instead of dropping
+	// bursts. Report also stops waiting when its context is done. Zero
+	// drops immediately, so that Report never blocks.
+	BlockTimeout time.Duration
+
+	// ReportTimeout bounds each call to the wrapped Reporter, whose context
+	// is canceled after it. Zero means no timeout.
+	ReportTimeout time.Duration
+}
+
+// Async returns an AsyncReporter with a single worker that queues up to
+// size errors for r. Errors reported while the queue is full are dropped
+// and counted.
+// The context passed to r carries the values of the reporting context but
+// is not canceled with it, so errors can be delivered after a request
+// completes.
+func Async(r Reporter, size int) *AsyncReporter {
+	return NewAsyncReporter(r, AsyncOptions{QueueSize: size})
+}
+
+// NewAsyncReporter returns an AsyncReporter reporting errors to r as
+// configured by opts. The number of goroutines it starts is bounded by
+// opts.Workers, and they exit when the reporter is shut down, so it cannot
+// leak goroutines; shut it down with Shutdown or Close when it is no
+// longer used.
+func NewAsyncReporter(r Reporter, opts AsyncOptions) *AsyncReporter {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize < 0 {
+		opts.QueueSize = 0
+	}
+
+	a := &AsyncReporter{
+		r:        r,
+		opts:     opts,
+		queue:    make(chan report, opts.QueueSize),
+		done:     make(chan struct{}),
+		stopping: make(chan struct{}),
+	}
+	a.base, a.cancel = context.WithCancel(context.Background())
+
+	a.wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go a.run()
+	}
+	go func() {
+		a.wg.Wait()
+		close(a.done)
+	}()
+
+	return a
+}
+
+func (a *AsyncReporter) run() {
+	defer a.wg.Done()
+
+	for rep := range a.queue {
+		if atomic.LoadInt32(&a.abandon) != 0 {
+			a.drop()
+			continue
+		}
+		a.report(rep)
+	}
+}
+
+func (a *AsyncReporter) report(rep report) {
+	ctx := context.Context(a.base)
+	if a.opts.ReportTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.opts.ReportTimeout)
+		defer cancel()
+	}
+
+	a.r.Report(withValues{ctx, rep.ctx}, rep.err)
+}
+
+// Report queues err. It does not block, unless BlockTimeout is set and the
+// queue is full.
+func (a *AsyncReporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	rep := report{ctx: ctx, err: err}
+
+	a.mu.RLock()
+	select {
+	case <-a.stopping:
+		a.mu.RUnlock()
+		a.drop()
+		return
+	default:
+	}
+	select {
+	case a.queue <- rep:
+		a.mu.RUnlock()
+		return
+	default:
+	}
+	if a.opts.BlockTimeout <= 0 {
+		a.mu.RUnlock()
+		a.drop()
+		return
+	}
+	a.senders.Add(1)
+	a.mu.RUnlock()
+	defer a.senders.Done()
+
+	timer := time.NewTimer(a.opts.BlockTimeout)
+	defer timer.Stop()
+
+	select {
+	case a.queue <- rep:
+	case <-timer.C:
+		a.drop()
+	case <-ctx.Done():
+		a.drop()
+	case <-a.stopping:
+		a.drop()
+	}
+}
+
+func (a *AsyncReporter) drop() {
+	atomic.AddUint64(&a.dropped, 1)
+}
+
+// Dropped returns the number of errors dropped because the queue was full,
+// the reporter was shut down or Shutdown gave up waiting for them.
+func (a *AsyncReporter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Shutdown stops accepting errors and waits until the queued errors have
+// been reported, or until ctx is done. In the latter case, the errors still
+// queued are dropped, the contexts of the reports in flight are canceled
+// and ctx's error is returned; the workers exit as soon as the reports in
+// flight return. Reports blocked waiting for room in the queue are dropped
+// at once.
+func (a *AsyncReporter) Shutdown(ctx context.Context) error {
+	a.stop.Do(func() {
+		close(a.stopping)
+		go a.closeQueue()
+	})
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		a.shutdown.Do(func() {
+			atomic.StoreInt32(&a.abandon, 1)
+			a.cancel()
+		})
+		return ctx.Err()
+	}
+}
+
+// closeQueue closes the queue once the reports that may be sending to it
+// have given up, which they do as soon as stopping is closed.
+func (a *AsyncReporter) closeQueue() {
+	// Report checks stopping and registers in senders while holding mu,
+	// so no sender registers after the lock is acquired here.
+	a.mu.Lock()
+	a.mu.Unlock()
+
+	a.senders.Wait()
+	close(a.queue)
+}
+
+// Close stops accepting errors and waits until the queued errors have
+// been reported, as Shutdown does without a deadline.
+func (a *AsyncReporter) Close() {
+	a.Shutdown(context.Background())
+}
+
+// withValues is a context with the deadline and cancellation of its
+// Context and the values of another context.
+type withValues struct {
+	context.Context
+	values context.Context
+}
+
+func (c withValues) Value(key interface{}) interface{} { return c.values.Value(key) }
+
+// JSONReporter writes each error to a writer as one line of the JSON wire
+// format of ToJSON.
+type JSONReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONReporter returns a JSONReporter writing to w.
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{w: w}
+}
+
+// NewStderrReporter returns a JSONReporter writing to standard error.
+func NewStderrReporter() *JSONReporter {
+	return NewJSONReporter(os.Stderr)
+}
+
+// Report writes err as a line of JSON. Errors that cannot be encoded are
+// skipped.
+func (r *JSONReporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	data, jerr := ToJSON(err)
+	if jerr != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.w.Write(append(data, '\n'))
+}
+
+// WebhookReporter posts each error to an HTTP endpoint, in the JSON wire
+// format of ToJSON.
+type WebhookReporter struct {
+	// URL is the endpoint errors are posted to.
+	URL string
+
+	// Client is used to send the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// OnError, if not nil, is called when an error cannot be delivered.
+	OnError func(error)
+}
+
+// Report posts err to r.URL. Responses with a status other than 2xx are
+// delivery failures.
+func (r *WebhookReporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	if derr := r.post(ctx, err); derr != nil && r.OnError != nil {
+		r.OnError(derr)
+	}
+}
+
+func (r *WebhookReporter) post(ctx context.Context, err error) error {
+	data, jerr := ToJSON(err)
+	if jerr != nil {
+		return WithMessage(jerr, "errors: webhook report")
+	}
+
+	req, rerr := http.NewRequest(http.MethodPost, r.URL, bytes.NewReader(data))
+	if rerr != nil {
+		return WithMessage(rerr, "errors: webhook report")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, rerr := client.Do(req)
+	if rerr != nil {
+		return WithMessage(rerr, "errors: webhook report")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Errorf("errors: webhook report: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
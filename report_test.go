@@ -0,0 +1,238 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordReporter struct {
+	mu   sync.Mutex
+	errs []error
+	ctxs []context.Context
+}
+
+func (r *recordReporter) Report(ctx context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+	r.ctxs = append(r.ctxs, ctx)
+}
+
+func TestFanOut(t *testing.T) {
+	var a, b recordReporter
+	r := FanOut(&a, &b)
+
+	r.Report(context.Background(), NewCode("test.report"))
+	r.Report(context.Background(), nil)
+
+	if len(a.errs) != 1 || len(b.errs) != 1 {
+		t.Errorf("FanOut: got %d and %d errors, want 1 and 1", len(a.errs), len(b.errs))
+	}
+}
+
+func TestSample(t *testing.T) {
+	var none, all recordReporter
+	for i := 0; i < 10; i++ {
+		Sample(&none, 0).Report(context.Background(), NewCode("test.report"))
+		Sample(&all, 1).Report(context.Background(), NewCode("test.report"))
+	}
+
+	if len(none.errs) != 0 || len(all.errs) != 10 {
+		t.Errorf("Sample: got %d and %d errors, want 0 and 10", len(none.errs), len(all.errs))
+	}
+}
+
+func TestAsync(t *testing.T) {
+	var rec recordReporter
+	r := Async(&rec, 10)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "t-1"))
+	r.Report(ctx, NewCode("test.report"))
+	cancel()
+	r.Close()
+	r.Report(context.Background(), NewCode("test.report"))
+
+	if len(rec.errs) != 1 {
+		t.Fatalf("Async: got %d errors, want 1", len(rec.errs))
+	}
+	if got := rec.ctxs[0].Err(); got != nil {
+		t.Errorf("Async: context error: got %v, want nil", got)
+	}
+	if got := rec.ctxs[0].Value(traceKey{}); got != "t-1" {
+		t.Errorf("Async: context value: got %v, want t-1", got)
+	}
+	if got := r.Dropped(); got != 1 {
+		t.Errorf("Dropped: got %d, want 1", got)
+	}
+}
+
+// blockReporter blocks each report until release is closed or the
+// context passed to it is done.
+type blockReporter struct {
+	started chan struct{}
+	release chan struct{}
+	recordReporter
+}
+
+func (r *blockReporter) Report(ctx context.Context, err error) {
+	r.started <- struct{}{}
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+	}
+	r.recordReporter.Report(ctx, err)
+}
+
+func TestNewAsyncReporter(t *testing.T) {
+	rec := &blockReporter{started: make(chan struct{}, 2), release: make(chan struct{})}
+	r := NewAsyncReporter(rec, AsyncOptions{Workers: 2, QueueSize: 1, BlockTimeout: 10 * time.Millisecond})
+
+	r.Report(context.Background(), NewCode("test.report"))
+	r.Report(context.Background(), NewCode("test.report"))
+	<-rec.started
+	<-rec.started
+
+	// Both workers are busy: the third error is queued, the fourth waits
+	// for room until BlockTimeout and is dropped.
+	r.Report(context.Background(), NewCode("test.report"))
+	r.Report(context.Background(), NewCode("test.report"))
+	if got := r.Dropped(); got != 1 {
+		t.Errorf("Dropped: got %d, want 1", got)
+	}
+
+	// A canceled context stops the wait at once.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Report(ctx, NewCode("test.report"))
+	if got := r.Dropped(); got != 2 {
+		t.Errorf("Dropped: got %d, want 2", got)
+	}
+
+	close(rec.release)
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if len(rec.errs) != 3 {
+		t.Errorf("NewAsyncReporter: got %d errors, want 3", len(rec.errs))
+	}
+}
+
+func TestAsyncReporterShutdown(t *testing.T) {
+	rec := &blockReporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	r := NewAsyncReporter(rec, AsyncOptions{QueueSize: 2})
+
+	r.Report(context.Background(), NewCode("test.report"))
+	<-rec.started
+	r.Report(context.Background(), NewCode("test.report"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown: got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The report in flight is canceled and the queued one dropped, so the
+	// worker exits.
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if len(rec.errs) != 1 {
+		t.Fatalf("Shutdown: got %d errors, want 1", len(rec.errs))
+	}
+	if rec.ctxs[0].Err() != context.Canceled {
+		t.Errorf("Shutdown: context error: got %v, want %v", rec.ctxs[0].Err(), context.Canceled)
+	}
+	if got := r.Dropped(); got != 1 {
+		t.Errorf("Dropped: got %d, want 1", got)
+	}
+}
+
+func TestAsyncReporterShutdownBlockedReport(t *testing.T) {
+	rec := &blockReporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	r := NewAsyncReporter(rec, AsyncOptions{QueueSize: 1, BlockTimeout: time.Hour})
+
+	r.Report(context.Background(), NewCode("test.report"))
+	<-rec.started
+	r.Report(context.Background(), NewCode("test.report"))
+
+	reported := make(chan struct{})
+	go func() {
+		r.Report(context.Background(), NewCode("test.report"))
+		close(reported)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := r.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown: returned after %v, want its deadline honored", elapsed)
+	}
+
+	<-reported
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestAsyncReporterReportTimeout(t *testing.T) {
+	rec := &blockReporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	r := NewAsyncReporter(rec, AsyncOptions{QueueSize: 1, ReportTimeout: 10 * time.Millisecond})
+
+	r.Report(context.Background(), NewCode("test.report"))
+	r.Close()
+
+	if len(rec.errs) != 1 {
+		t.Fatalf("ReportTimeout: got %d errors, want 1", len(rec.errs))
+	}
+	if rec.ctxs[0].Err() != context.DeadlineExceeded {
+		t.Errorf("ReportTimeout: context error: got %v, want %v", rec.ctxs[0].Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	NewJSONReporter(&buf).Report(context.Background(), NewCode("test.report", "failed"))
+
+	want := `{"schema_version":1,"chain":[{"code":"test.report","message":"failed"}]}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("JSONReporter: got %q, want %q", got, want)
+	}
+}
+
+func TestWebhookReporter(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	var failures []error
+	r := &WebhookReporter{URL: srv.URL, OnError: func(err error) { failures = append(failures, err) }}
+	r.Report(context.Background(), NewCode("test.report", "failed"))
+
+	if want := `{"schema_version":1,"chain":[{"code":"test.report","message":"failed"}]}`; string(body) != want {
+		t.Errorf("WebhookReporter: got body %s, want %s", body, want)
+	}
+	if len(failures) != 0 {
+		t.Errorf("WebhookReporter: got failures %v", failures)
+	}
+
+	r.URL = srv.URL + "/fail"
+	r.Report(context.Background(), NewCode("test.report", "failed"))
+	if len(failures) != 1 {
+		t.Errorf("WebhookReporter: got %d failures, want 1", len(failures))
+	}
+}
@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Owned is implemented by Coders that declare the team owning their code.
+// MustRegister checks the owner against the ranges reserved by ReserveRange.
+type Owned interface {
+	Owner() string
+}
+
+// reservation is a range of codes reserved for an owner. Numeric ranges
+// share a prefix and bound the numeric suffix; other ranges reserve every
+// code starting with prefix.
+type reservation struct {
+	prefix  string
+	lo, hi  uint64
+	numeric bool
+	owner   string
+}
+
+// reservations are guarded by codeMux.
+var reservations []reservation
+
+// ReserveRange reserves the codes from from to to for owner, so that
+// MustRegister rejects them for Coders of other owners:
+//
+//     errors.ReserveRange("PAY-1000", "PAY-1999", "payments-team")
+//
+// Bounds with the same prefix and a numeric suffix reserve the codes with
+// that prefix and a number in the range, inclusive. Equal bounds without a
+// numeric suffix reserve every code starting with them, such as "auth.".
+// ReserveRange fails if the bounds are invalid or if the range may overlap
+// a range reserved by another owner.
+func ReserveRange(from, to, owner string) error {
+	r, err := newReservation(from, to, owner)
+	if err != nil {
+		return err
+	}
+
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	for _, other := range reservations {
+		if other.owner != owner && r.overlaps(other) {
+			return Errorf("errors: range %s-%s of %s overlaps a range of %s", from, to, owner, other.owner)
+		}
+	}
+	reservations = append(reservations, r)
+
+	return nil
+}
+
+func newReservation(from, to, owner string) (reservation, error) {
+	fromPrefix, lo, fromNumeric := splitNumeric(from)
+	toPrefix, hi, toNumeric := splitNumeric(to)
+
+	switch {
+	case fromNumeric && toNumeric && fromPrefix == toPrefix && lo <= hi:
+		return reservation{prefix: fromPrefix, lo: lo, hi: hi, numeric: true, owner: owner}, nil
+	case !fromNumeric && from == to && from != "":
+		return reservation{prefix: from, owner: owner}, nil
+	}
+
+	return reservation{}, Errorf("errors: invalid range %s-%s", from, to)
+}
+
+// splitNumeric splits code into a prefix and a numeric suffix, and reports
+// whether there is a numeric suffix.
+func splitNumeric(code string) (string, uint64, bool) {
+	i := len(code)
+	for i > 0 && code[i-1] >= '0' && code[i-1] <= '9' {
+		i--
+	}
+	if i == len(code) {
+		return code, 0, false
+	}
+
+	n, err := strconv.ParseUint(code[i:], 10, 64)
+	if err != nil {
+		return code, 0, false
+	}
+
+	return code[:i], n, true
+}
+
+func (r reservation) contains(code string) bool {
+	if !r.numeric {
+		return strings.HasPrefix(code, r.prefix)
+	}
+
+	prefix, n, ok := splitNumeric(code)
+	return ok && prefix == r.prefix && n >= r.lo && n <= r.hi
+}
+
+// overlaps reports whether r and other may reserve a common code. Prefix
+// ranges are compared conservatively.
+func (r reservation) overlaps(other reservation) bool {
+	if r.numeric && other.numeric {
+		return r.prefix == other.prefix && r.lo <= other.hi && other.lo <= r.hi
+	}
+
+	return strings.HasPrefix(r.prefix, other.prefix) || strings.HasPrefix(other.prefix, r.prefix)
+}
+
+// checkReservation returns an error if the code of coder is reserved for
+// another owner. It is called with codeMux held.
+func checkReservation(coder Coder) error {
+	owner := ""
+	if o, ok := coder.(Owned); ok {
+		owner = o.Owner()
+	}
+
+	for _, r := range reservations {
+		if r.contains(coder.Code()) && r.owner != owner {
+			return Errorf("code: %s is reserved for %s", coder.Code(), r.owner)
+		}
+	}
+
+	return nil
+}
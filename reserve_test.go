@@ -0,0 +1,70 @@
+package errors
+
+import "testing"
+
+type ownedCoder struct {
+	testCoder
+	owner string
+}
+
+func (c ownedCoder) Owner() string { return c.owner }
+
+func TestReserveRange(t *testing.T) {
+	defer func() { reservations = nil }()
+
+	tests := []struct {
+		from, to, owner string
+		ok              bool
+	}{
+		{"TPAY-1000", "TPAY-1999", "payments", true},
+		{"TPAY-2000", "TPAY-2999", "billing", true},
+		{"TPAY-1500", "TPAY-2500", "billing", false},
+		{"TPAY-1500", "TPAY-1600", "payments", true},
+		{"test.auth.", "test.auth.", "identity", true},
+		{"test.auth.token.", "test.auth.token.", "payments", false},
+		{"TPAY-1999", "TPAY-1000", "payments", false},
+		{"TPAY-1000", "TBILL-1999", "payments", false},
+		{"test.a", "test.b", "payments", false},
+	}
+
+	for i, tt := range tests {
+		err := ReserveRange(tt.from, tt.to, tt.owner)
+		if (err == nil) != tt.ok {
+			t.Errorf("test %d: ReserveRange(%q, %q, %q): got %v, want ok %v", i+1, tt.from, tt.to, tt.owner, err, tt.ok)
+		}
+	}
+}
+
+func TestMustRegisterReserved(t *testing.T) {
+	defer func() { reservations = nil }()
+
+	if err := ReserveRange("TRES-100", "TRES-199", "payments"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReserveRange("test.reserve.", "test.reserve.", "identity"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		coder  Coder
+		panics bool
+	}{
+		{ownedCoder{testCoder{code: "TRES-150"}, "payments"}, false},
+		{ownedCoder{testCoder{code: "TRES-151"}, "billing"}, true},
+		{testCoder{code: "TRES-152"}, true},
+		{testCoder{code: "TRES-200"}, false},
+		{ownedCoder{testCoder{code: "test.reserve.login"}, "identity"}, false},
+		{testCoder{code: "test.reserve.logout"}, true},
+	}
+
+	for i, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); (r != nil) != tt.panics {
+					t.Errorf("test %d: MustRegister(%s): got panic %v, want panic %v", i+1, tt.coder.Code(), r, tt.panics)
+				}
+			}()
+			MustRegister(tt.coder)
+		}()
+	}
+}
@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// RetryAfterer is implemented by Coders whose errors may be retried after
+// a delay, such as rate limiting or temporary unavailability.
+type RetryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// WithRetryAfter annotates err with the delay after which the failed
+// operation may be retried. It takes precedence over the delay of Coders
+// deeper in err's chain.
+// If err is nil, WithRetryAfter returns nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withRetryAfter{
+		error: err,
+		after: d,
+	}
+}
+
+type withRetryAfter struct {
+	error
+	after time.Duration
+}
+
+func (w *withRetryAfter) Cause() error { return w.error }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withRetryAfter) Unwrap() error { return w.error }
+
+func (w *withRetryAfter) RetryAfter() time.Duration { return w.after }
+
+func (w *withRetryAfter) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.Cause())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// RetryAfter returns the delay after which the operation that failed with
+// err may be retried. It is taken from the outermost WithRetryAfter
+// annotation or coded error whose Coder implements RetryAfterer.
+// The boolean reports whether a delay was found.
+func RetryAfter(err error) (time.Duration, bool) {
+	var after time.Duration
+	found := false
+	walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok {
+			err = wc
+		}
+
+		switch e := err.(type) {
+		case *withRetryAfter:
+			after, found = e.after, true
+		case *Error:
+			if r, ok := e.Coder().(RetryAfterer); ok {
+				after, found = r.RetryAfter(), true
+			}
+		}
+
+		return !found
+	})
+
+	return after, found
+}
+
+// Backoffer is implemented by Coders whose errors are retried with an
+// exponential backoff other than the default of the RetryPolicy.
+// Backoff returns the delay before the first retry and the maximum delay.
+type Backoffer interface {
+	Backoff() (base, max time.Duration)
+}
+
+// The defaults of RetryPolicy returned by PolicyFromCodes.
+const (
+	DefaultRetryAttempts  = 3
+	DefaultRetryBaseDelay = 100 * time.Millisecond
+	DefaultRetryMaxDelay  = 10 * time.Second
+)
+
+// RetryPolicy decides whether and when the operation that failed with an
+// error is retried, based on the codes in the error's chain. It holds no
+// state of its own, so one policy may be shared by any number of clients.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first
+	// one. If it is zero or negative, the number of attempts is unlimited.
+	MaxAttempts int
+
+	// BaseDelay and MaxDelay bound the exponential backoff of errors whose
+	// Coder does not implement Backoffer.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	codes []string
+}
+
+// PolicyFromCodes returns a RetryPolicy retrying errors whose outermost
+// code is one of the retryable codes, or whose Coder has FlagRetryable,
+// using the default attempts and delays.
+func PolicyFromCodes(retryable ...string) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: DefaultRetryAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		codes:       append([]string(nil), retryable...),
+	}
+}
+
+// ShouldRetry reports whether the operation that failed with err on the
+// given attempt, counted from 1, should be retried, and the delay to wait
+// before retrying.
+//
+// Errors whose Coder has FlagPermanent are never retried. The delay is the
+// one reported by RetryAfter if any, and otherwise doubles with each
+// attempt, starting at the base delay and capped at the maximum delay of
+// the Coder's Backoffer or of the policy.
+func (p *RetryPolicy) ShouldRetry(err error, attempt int) (time.Duration, bool) {
+	if err == nil || p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	flags := Flags(err)
+	if flags&FlagPermanent != 0 {
+		return 0, false
+	}
+	if flags&FlagRetryable == 0 && !p.retryable(FirstCode(err)) {
+		return 0, false
+	}
+
+	if after, ok := RetryAfter(err); ok {
+		return after, true
+	}
+
+	return p.backoff(err, attempt), true
+}
+
+// retryable reports whether code is one of the retryable codes of p.
+func (p *RetryPolicy) retryable(code string) bool {
+	for _, c := range p.codes {
+		if c == code {
+			return code != ""
+		}
+	}
+
+	return false
+}
+
+// backoff returns the exponential delay before retrying attempt.
+func (p *RetryPolicy) backoff(err error, attempt int) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if wc := codeOf(err); wc != nil {
+		if b, ok := wc.Coder().(Backoffer); ok {
+			base, max = b.Backoff()
+		}
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay > 0 && delay < math.MaxInt64/2; i++ {
+		if max > 0 && delay >= max {
+			break
+		}
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+
+	return delay
+}
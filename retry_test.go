@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type retryCoder struct {
+	testCoder
+	after time.Duration
+}
+
+func (c retryCoder) RetryAfter() time.Duration { return c.after }
+
+func TestRetryAfter(t *testing.T) {
+	Register(retryCoder{testCoder{code: "test.retry.limited", status: http.StatusTooManyRequests, message: "slow down"}, time.Minute})
+
+	tests := []struct {
+		err   error
+		after time.Duration
+		ok    bool
+	}{
+		{nil, 0, false},
+		{New("plain"), 0, false},
+		{Wrap(NewCode("test.retry.limited"), "call"), time.Minute, true},
+		{WithRetryAfter(NewCode("test.retry.limited"), time.Second), time.Second, true},
+		{WithRetryAfter(New("plain"), 0), 0, true},
+	}
+
+	for i, tt := range tests {
+		after, ok := RetryAfter(tt.err)
+		if after != tt.after || ok != tt.ok {
+			t.Errorf("test %d: RetryAfter(%v): got %v, %v, want %v, %v", i+1, tt.err, after, ok, tt.after, tt.ok)
+		}
+	}
+
+	if WithRetryAfter(nil, time.Second) != nil {
+		t.Errorf("WithRetryAfter(nil): want nil")
+	}
+}
+
+func TestWithRetryAfterFormat(t *testing.T) {
+	err := WithRetryAfter(NewCode("test.retry", "busy"), time.Second)
+
+	if got, want := fmt.Sprintf("%v", err), "test.retry - busy"; got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", err), fmt.Sprintf("%+v", err.(*withRetryAfter).error); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+}
+
+func TestWriteHTTPRetryAfter(t *testing.T) {
+	Register(testCoder{code: "test.retry.unavailable", status: http.StatusServiceUnavailable, message: "unavailable"})
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, WithRetryAfter(NewCode("test.retry.unavailable"), 1500*time.Millisecond))
+	if got, want := rec.Header().Get("Retry-After"), "2"; got != want {
+		t.Errorf("Retry-After: got %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	WriteHTTP(rec, WithRetryAfter(New("plain"), time.Second))
+	if got := rec.Header().Get("Retry-After"); got != "" {
+		t.Errorf("Retry-After for 500: got %q, want empty", got)
+	}
+}
+
+type backoffCoder struct {
+	testCoder
+	base, max time.Duration
+}
+
+func (c backoffCoder) Backoff() (time.Duration, time.Duration) { return c.base, c.max }
+
+func TestRetryPolicy(t *testing.T) {
+	Register(testCoder{code: "test.policy.busy"})
+	Register(testCoder{code: "test.policy.invalid"})
+	Register(flagCoder{testCoder{code: "test.policy.flagged"}, FlagRetryable})
+	Register(flagCoder{testCoder{code: "test.policy.permanent"}, FlagPermanent})
+	Register(backoffCoder{testCoder{code: "test.policy.slow"}, time.Second, 3 * time.Second})
+
+	p := PolicyFromCodes("test.policy.busy", "test.policy.slow", "test.policy.permanent")
+	p.MaxAttempts = 5
+
+	tests := []struct {
+		err     error
+		attempt int
+		delay   time.Duration
+		ok      bool
+	}{
+		{nil, 1, 0, false},
+		{New("plain"), 1, 0, false},
+		{NewCode("test.policy.invalid"), 1, 0, false},
+		{NewCode("test.policy.permanent"), 1, 0, false},
+		{NewCode("test.policy.busy"), 1, DefaultRetryBaseDelay, true},
+		{Wrap(NewCode("test.policy.busy"), "call"), 3, 4 * DefaultRetryBaseDelay, true},
+		{NewCode("test.policy.busy"), 5, 0, false},
+		{NewCode("test.policy.flagged"), 2, 2 * DefaultRetryBaseDelay, true},
+		{NewCode("test.policy.slow"), 1, time.Second, true},
+		{NewCode("test.policy.slow"), 2, 2 * time.Second, true},
+		{NewCode("test.policy.slow"), 4, 3 * time.Second, true},
+		{WithRetryAfter(NewCode("test.policy.slow"), time.Minute), 4, time.Minute, true},
+	}
+
+	for i, tt := range tests {
+		delay, ok := p.ShouldRetry(tt.err, tt.attempt)
+		if delay != tt.delay || ok != tt.ok {
+			t.Errorf("test %d: ShouldRetry(%v, %d): got %v, %v, want %v, %v", i+1, tt.err, tt.attempt, delay, ok, tt.delay, tt.ok)
+		}
+	}
+
+	p.MaxAttempts = 0
+	if delay, ok := p.ShouldRetry(NewCode("test.policy.busy"), 100); !ok || delay != DefaultRetryMaxDelay {
+		t.Errorf("unlimited attempts: got %v, %v, want %v, true", delay, ok, DefaultRetryMaxDelay)
+	}
+}
@@ -0,0 +1,27 @@
+package errors
+
+// NewSentinel returns a coded error for an expected condition of the
+// control flow, such as the end of a stream or a cache miss, to be declared
+// once as a package variable and returned as is:
+//
+//     var ErrCacheMiss = errors.NewSentinel("cache.miss")
+//
+//     if !ok {
+//             return nil, ErrCacheMiss
+//     }
+//
+// Unlike the errors of NewCode, a sentinel records no stack trace and no
+// instance ID, so returning it costs no allocation, and it is comparable:
+// callers may test for it with == as well as with IsCode, HasCode and the
+// standard errors.Is. Its message is the message of the registered Coder of
+// code, resolved each time it is used, so that the sentinel may be declared
+// before its Coder is registered.
+// Wrap the sentinel, for example with WrapCode, where an occurrence needs a
+// stack trace or params.
+func NewSentinel(code string) error {
+	return &Error{
+		code:   code,
+		source: messageLazy,
+		stack:  &stack{},
+	}
+}
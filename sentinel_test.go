@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+var errTestSentinel = NewSentinel("test.sentinel")
+
+func TestNewSentinel(t *testing.T) {
+	Register(testCoder{code: "test.sentinel", message: "sentinel"})
+
+	find := func() error { return errTestSentinel }
+	if err := find(); err != errTestSentinel {
+		t.Errorf("NewSentinel: got %v, want the sentinel itself", err)
+	}
+	if !IsCode(errTestSentinel, "test.sentinel") {
+		t.Error("IsCode: got false, want true")
+	}
+	if !HasCode(WrapCode(errTestSentinel, "test.wrap"), "test.sentinel") {
+		t.Error("HasCode: got false for a wrapped sentinel, want true")
+	}
+
+	if got, want := errTestSentinel.Error(), "test.sentinel - sentinel"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", errTestSentinel), "test.sentinel - sentinel"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := find(); !IsCode(err, "test.sentinel") {
+			t.Fatal("IsCode: got false, want true")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("NewSentinel: got %v allocations, want 0", allocs)
+	}
+}
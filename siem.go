@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToECS returns the fields of err in the Elastic Common Schema, keyed by
+// their dotted ECS names, for ingestion by security and observability
+// pipelines without custom mappers:
+//
+//     event.code                 outermost code
+//     error.code                 outermost code
+//     error.id                   instance ID
+//     error.type                 Go type of the root cause
+//     error.message              Error text
+//     error.stack_trace          err formatted with %+v
+//     http.response.status_code  status of the registered Coder
+//     labels.<name>              params, formatted as strings
+//
+// Fields without a value are omitted. The serialization limits are applied.
+// If err is nil, ToECS returns nil.
+func ToECS(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+
+	l := serializationLimits()
+	fields := map[string]interface{}{
+		"error.type":        fmt.Sprintf("%T", RootCause(err)),
+		"error.message":     l.limitMessage(chainError(err)),
+		"error.stack_trace": fmt.Sprintf("%+v", err),
+	}
+
+	if wc := codeOf(err); wc != nil {
+		fields["event.code"] = wc.code
+		fields["error.code"] = wc.code
+		if wc.id != "" {
+			fields["error.id"] = wc.id
+		}
+		if coder := wc.Coder(); coder != nil {
+			fields["http.response.status_code"] = coder.StatusCode()
+		}
+		for k, v := range l.limitParams(wc.params) {
+			fields["labels."+k] = fmt.Sprint(v)
+		}
+	}
+
+	return fields
+}
+
+// ToCEF returns err as an ArcSight Common Event Format record of the given
+// device vendor, product and version. The signature ID is the outermost
+// code, the name its message and the severity derived from LevelOf; the
+// Error text, instance ID and params are sent as extensions:
+//
+//     CEF:0|Acme|Billing|1.2|payment.declined|card declined|3|msg=payment.declined - card declined externalId=... amount=12
+//
+// Params whose names are not alphanumeric are omitted, as CEF does not
+// allow them as extension keys. The serialization limits are applied.
+// If err is nil, ToCEF returns the empty string.
+func ToCEF(err error, vendor, product, version string) string {
+	if err == nil {
+		return ""
+	}
+
+	l := serializationLimits()
+	text := l.limitMessage(chainError(err))
+	signature, name := "error", text
+	var params map[string]interface{}
+	id := ""
+	if wc := codeOf(err); wc != nil {
+		signature, name, id = wc.code, l.limitMessage(wc.Message()), wc.id
+		params = l.limitParams(wc.params)
+	}
+
+	var b strings.Builder
+	b.WriteString("CEF:0")
+	for _, field := range []string{vendor, product, version, signature, name, strconv.Itoa(cefSeverity(LevelOf(err)))} {
+		b.WriteString("|" + cefHeaderEscaper.Replace(field))
+	}
+	b.WriteString("|msg=" + cefExtensionEscaper.Replace(text))
+	if id != "" {
+		b.WriteString(" externalId=" + cefExtensionEscaper.Replace(id))
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if cefKey(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(" " + k + "=" + cefExtensionEscaper.Replace(fmt.Sprint(params[k])))
+	}
+
+	return b.String()
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// cefSeverity maps a Level to the 0 to 10 severity scale of CEF.
+func cefSeverity(level Level) int {
+	switch {
+	case level >= LevelError:
+		return 7
+	case level >= LevelWarn:
+		return 5
+	case level >= LevelInfo:
+		return 3
+	}
+	return 1
+}
+
+// cefKey reports whether k can be used as a CEF extension key.
+func cefKey(k string) bool {
+	if k == "" {
+		return false
+	}
+
+	for _, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
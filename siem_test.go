@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestToECS(t *testing.T) {
+	Register(testCoder{code: "test.siem.declined", status: http.StatusPaymentRequired, message: "card declined"})
+
+	if got := ToECS(nil); got != nil {
+		t.Errorf("ToECS(nil): got %v, want nil", got)
+	}
+
+	err := WrapCodeWithParams(io.EOF, "test.siem.declined", map[string]interface{}{"amount": 12})
+	fields := ToECS(err)
+
+	want := map[string]interface{}{
+		"event.code":                "test.siem.declined",
+		"error.code":                "test.siem.declined",
+		"error.type":                "*errors.errorString",
+		"error.message":             "test.siem.declined - card declined: EOF",
+		"http.response.status_code": http.StatusPaymentRequired,
+		"labels.amount":             "12",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("ToECS()[%q]: got %v, want %v", k, fields[k], v)
+		}
+	}
+	if st, _ := fields["error.stack_trace"].(string); !strings.Contains(st, "TestToECS") {
+		t.Errorf("ToECS()[error.stack_trace]: got %q", st)
+	}
+
+	fields = ToECS(io.EOF)
+	if _, ok := fields["event.code"]; ok || fields["error.message"] != "EOF" {
+		t.Errorf("ToECS(EOF): got %v", fields)
+	}
+}
+
+func TestToCEF(t *testing.T) {
+	Register(testCoder{code: "test.siem.denied", status: http.StatusForbidden, message: "access denied"})
+
+	if got := ToCEF(nil, "Acme", "Billing", "1.2"); got != "" {
+		t.Errorf("ToCEF(nil): got %q, want empty", got)
+	}
+
+	err := NewCodeWithParams("test.siem.denied", map[string]interface{}{"user": "a=b", "bad key": 1}, "role|admin")
+	want := `CEF:0|Acme|Billing|1.2|test.siem.denied|role\|admin|3|msg=test.siem.denied - role|admin user=a\=b`
+	if got := ToCEF(err, "Acme", "Billing", "1.2"); got != want {
+		t.Errorf("ToCEF():\n got %s\n want %s", got, want)
+	}
+
+	want = `CEF:0|Acme|Billing|1.2|error|line\\n|7|msg=line\\n`
+	if got := ToCEF(New(`line\n`), "Acme", "Billing", "1.2"); got != want {
+		t.Errorf("ToCEF(uncoded):\n got %s\n want %s", got, want)
+	}
+}
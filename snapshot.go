@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegistrySnapshot is the public contract of the registered codes at a point
+// in time. It is JSON serializable, so a snapshot can be committed and
+// compared with the registry of a later build in CI using DiffRegistries.
+type RegistrySnapshot struct {
+	Codes map[string]CodeContract `json:"codes"`
+}
+
+// CodeContract is the public contract of a code.
+type CodeContract struct {
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// Hash returns a hash of the contract that is stable across processes and
+// builds, so that contracts can be compared without their contents.
+func (c CodeContract) Hash() string {
+	h := sha256.New()
+	for _, s := range []string{strconv.Itoa(c.Status), c.Message, c.Reference} {
+		fmt.Fprintf(h, "%d:%s;", len(s), s)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Snapshot returns a snapshot of the registered codes.
+func Snapshot() RegistrySnapshot {
+	codeMux.RLock()
+	defer codeMux.RUnlock()
+
+	s := RegistrySnapshot{Codes: make(map[string]CodeContract, len(codes))}
+	for code, coder := range codes {
+		s.Codes[code] = CodeContract{
+			Status:    coder.StatusCode(),
+			Message:   coder.Message(),
+			Reference: coder.Reference(),
+		}
+	}
+
+	return s
+}
+
+// RegistryDiff lists the differences between two registry snapshots.
+// All lists are sorted by code.
+type RegistryDiff struct {
+	Added            []string
+	Removed          []string
+	StatusChanged    []ContractChange
+	MessageChanged   []ContractChange
+	ReferenceChanged []ContractChange
+}
+
+// ContractChange is a changed field of the contract of a code.
+type ContractChange struct {
+	Code string
+	Old  string
+	New  string
+
+	// Breaking is set for changes that break clients: any status change,
+	// and message changes that add or remove {param} placeholders.
+	Breaking bool
+}
+
+// DiffRegistries compares the snapshots old and new of a registry.
+func DiffRegistries(old, new RegistrySnapshot) RegistryDiff {
+	var d RegistryDiff
+
+	for _, code := range sortedCodes(old.Codes) {
+		o := old.Codes[code]
+		n, ok := new.Codes[code]
+		if !ok {
+			d.Removed = append(d.Removed, code)
+			continue
+		}
+
+		if o.Status != n.Status {
+			d.StatusChanged = append(d.StatusChanged, ContractChange{code, strconv.Itoa(o.Status), strconv.Itoa(n.Status), true})
+		}
+		if o.Message != n.Message {
+			breaking := strings.Join(placeholders(o.Message), ",") != strings.Join(placeholders(n.Message), ",")
+			d.MessageChanged = append(d.MessageChanged, ContractChange{code, o.Message, n.Message, breaking})
+		}
+		if o.Reference != n.Reference {
+			d.ReferenceChanged = append(d.ReferenceChanged, ContractChange{code, o.Reference, n.Reference, false})
+		}
+	}
+
+	for _, code := range sortedCodes(new.Codes) {
+		if _, ok := old.Codes[code]; !ok {
+			d.Added = append(d.Added, code)
+		}
+	}
+
+	return d
+}
+
+// Breaking reports whether d contains changes that break clients: removed
+// codes and breaking contract changes.
+func (d RegistryDiff) Breaking() bool {
+	if len(d.Removed) > 0 {
+		return true
+	}
+
+	for _, changes := range [][]ContractChange{d.StatusChanged, d.MessageChanged, d.ReferenceChanged} {
+		for _, c := range changes {
+			if c.Breaking {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// String returns a line per difference, breaking changes marked with "!".
+func (d RegistryDiff) String() string {
+	var b strings.Builder
+
+	for _, code := range d.Added {
+		fmt.Fprintf(&b, "  added %s\n", code)
+	}
+	for _, code := range d.Removed {
+		fmt.Fprintf(&b, "! removed %s\n", code)
+	}
+	for _, field := range []struct {
+		name    string
+		changes []ContractChange
+	}{{"status", d.StatusChanged}, {"message", d.MessageChanged}, {"reference", d.ReferenceChanged}} {
+		for _, c := range field.changes {
+			mark := " "
+			if c.Breaking {
+				mark = "!"
+			}
+			fmt.Fprintf(&b, "%s %s of %s: %q -> %q\n", mark, field.name, c.Code, c.Old, c.New)
+		}
+	}
+
+	return b.String()
+}
+
+func sortedCodes(contracts map[string]CodeContract) []string {
+	codes := make([]string, 0, len(contracts))
+	for code := range contracts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// placeholders returns the sorted {name} placeholders of tmpl.
+func placeholders(tmpl string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end < 0 {
+			break
+		}
+		names = append(names, tmpl[start+1:start+end])
+		tmpl = tmpl[start+end+1:]
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffRegistries(t *testing.T) {
+	old := RegistrySnapshot{Codes: map[string]CodeContract{
+		"a.removed": {Status: 400, Message: "gone"},
+		"b.status":  {Status: 400, Message: "bad"},
+		"c.wording": {Status: 404, Message: "file {name} not found"},
+		"d.params":  {Status: 404, Message: "file {name} not found"},
+		"e.same":    {Status: 500, Message: "oops", Reference: "https://example.com/e"},
+	}}
+	new := RegistrySnapshot{Codes: map[string]CodeContract{
+		"b.status":  {Status: 409, Message: "bad"},
+		"c.wording": {Status: 404, Message: "no file {name}"},
+		"d.params":  {Status: 404, Message: "file {path} not found"},
+		"e.same":    {Status: 500, Message: "oops", Reference: "https://example.com/e"},
+		"f.added":   {Status: 400, Message: "new"},
+	}}
+
+	d := DiffRegistries(old, new)
+	want := `  added f.added
+! removed a.removed
+! status of b.status: "400" -> "409"
+  message of c.wording: "file {name} not found" -> "no file {name}"
+! message of d.params: "file {name} not found" -> "file {path} not found"
+`
+	if got := d.String(); got != want {
+		t.Errorf("DiffRegistries: got\n%s\nwant\n%s", got, want)
+	}
+	if !d.Breaking() {
+		t.Errorf("Breaking: got false, want true")
+	}
+
+	if d := DiffRegistries(old, old); d.Breaking() || d.String() != "" {
+		t.Errorf("DiffRegistries(old, old): got %q", d.String())
+	}
+	if d := DiffRegistries(RegistrySnapshot{}, new); d.Breaking() || len(d.Added) != 5 {
+		t.Errorf("DiffRegistries(empty, new): got %q", d.String())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	Register(testCoder{code: "test.snapshot", status: 418, message: "teapot"})
+
+	data, err := json.Marshal(Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s RegistrySnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.Codes["test.snapshot"]
+	if c != (CodeContract{Status: 418, Message: "teapot"}) {
+		t.Errorf("Snapshot: got %+v", c)
+	}
+	if c.Hash() != (CodeContract{Status: 418, Message: "teapot"}).Hash() || c.Hash() == (CodeContract{Status: 418, Message: "teapot!"}).Hash() {
+		t.Errorf("Hash: not stable or not distinct")
+	}
+}
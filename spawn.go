@@ -0,0 +1,70 @@
+package errors
+
+import "context"
+
+type spawnKey struct{}
+
+// CaptureSpawnStack returns a copy of ctx carrying the stack trace at the
+// point CaptureSpawnStack is called. Pass it to a goroutine so that errors
+// created there with WrapCodeCtx include the stack of the spawning call site:
+//
+//     ctx = errors.CaptureSpawnStack(ctx)
+//     go func() {
+//             if err := work(ctx); err != nil {
+//                     errc <- errors.WrapCodeCtx(ctx, err, "worker.failed")
+//             }
+//     }()
+func CaptureSpawnStack(ctx context.Context) context.Context {
+	return context.WithValue(ctx, spawnKey{}, callers())
+}
+
+// SpawnStack returns the stack trace captured by CaptureSpawnStack in ctx,
+// or nil if there is none.
+func SpawnStack(ctx context.Context) StackTrace {
+	if st := spawnStack(ctx); st != nil {
+		return st.StackTrace()
+	}
+
+	return nil
+}
+
+func spawnStack(ctx context.Context) *stack {
+	if ctx == nil {
+		return nil
+	}
+
+	st, _ := ctx.Value(spawnKey{}).(*stack)
+	return st
+}
+
+// GoWrap returns a function running fn that annotates the error returned by
+// fn with code, the stack trace of the goroutine and, as spawn stack, the
+// stack trace at the point GoWrap is called. It is meant for spawning
+// goroutines, for example with errgroup:
+//
+//     g.Go(errors.GoWrap(task, "worker.failed"))
+//
+// If fn returns nil, the returned function returns nil.
+func GoWrap(fn func() error, code string, msgs ...string) func() error {
+	spawn := callers()
+
+	return func() error {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		err = checkCode(code, err)
+
+		return &Error{
+			code:    code,
+			message: explicitMessage(msgs),
+			source:  messageSourceOf(msgs),
+			cause:   err,
+			id:      errorID(err),
+			spawn:   spawn,
+			seq:     recordWrap(code),
+			stack:   callers(),
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestGoWrap(t *testing.T) {
+	run := GoWrap(func() error { return New("task failed") }, "test.spawn", "worker") // spawn site
+
+	errc := make(chan error)
+	go func() { errc <- run() }()
+	err := <-errc
+
+	if got, want := err.Error(), "test.spawn - worker: task failed"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	re := regexp.MustCompile(`(?s)test.spawn - worker\n.*spawned by:\ngithub.com/pkg/errors.TestGoWrap\n\t.+/spawn_test.go:11`)
+	if got := fmt.Sprintf("%+v", err); !re.MatchString(got) {
+		t.Errorf("%%+v: got %q, want spawn stack", got)
+	}
+
+	if err := GoWrap(func() error { return nil }, "test.spawn")(); err != nil {
+		t.Errorf("GoWrap(nil error): got %v, want nil", err)
+	}
+}
+
+func TestCaptureSpawnStack(t *testing.T) {
+	if st := SpawnStack(context.Background()); st != nil {
+		t.Errorf("SpawnStack without capture: got %v, want nil", st)
+	}
+
+	ctx := CaptureSpawnStack(context.Background()) // spawn site
+	if got := fmt.Sprintf("%+v", SpawnStack(ctx)[0]); !regexp.MustCompile(`TestCaptureSpawnStack\n\t.+/spawn_test.go:35$`).MatchString(got) {
+		t.Errorf("SpawnStack: got %q", got)
+	}
+
+	errc := make(chan error)
+	go func() { errc <- WrapCodeCtx(ctx, New("failed"), "test.spawn") }()
+	if got := fmt.Sprintf("%+v", <-errc); !regexp.MustCompile(`(?s)spawned by:\n.+spawn_test.go:35`).MatchString(got) {
+		t.Errorf("%%+v: got %q, want spawn stack", got)
+	}
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SpikeDetector counts the errors of each code over a sliding window and
+// calls OnCodeSpike when the count of a code reaches the threshold, for
+// in-process alerting. It implements Reporter, so it can be combined with
+// other reporters using FanOut.
+type SpikeDetector struct {
+	window    time.Duration
+	threshold int
+	onSpike   func(code string, count int, window time.Duration)
+	now       func() time.Time
+
+	mu     sync.Mutex
+	counts map[string]*spikeCount
+}
+
+type spikeCount struct {
+	times   []time.Time // oldest first, at most threshold entries
+	spiking bool
+}
+
+// NewSpikeDetector returns a SpikeDetector calling onSpike when threshold
+// errors of the same code are observed within window. onSpike is called
+// once when a code starts spiking, and again only after the count of the
+// code has fallen below threshold.
+func NewSpikeDetector(window time.Duration, threshold int, onSpike func(code string, count int, window time.Duration)) *SpikeDetector {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &SpikeDetector{
+		window:    window,
+		threshold: threshold,
+		onSpike:   onSpike,
+		now:       time.Now,
+		counts:    map[string]*spikeCount{},
+	}
+}
+
+// Observe counts the outermost code of err. Errors without a code are
+// ignored.
+func (d *SpikeDetector) Observe(err error) {
+	code := FirstCode(err)
+	if code == "" {
+		return
+	}
+
+	if count, ok := d.observe(code); ok && d.onSpike != nil {
+		d.onSpike(code, count, d.window)
+	}
+}
+
+// Report observes err, see Observe.
+func (d *SpikeDetector) Report(ctx context.Context, err error) {
+	d.Observe(err)
+}
+
+// observe records an error of code and reports whether the code started
+// spiking, with its count.
+func (d *SpikeDetector) observe(code string) (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	c := d.counts[code]
+	if c == nil {
+		c = &spikeCount{}
+		d.counts[code] = c
+	}
+
+	cutoff := now.Add(-d.window)
+	i := 0
+	for i < len(c.times) && !c.times[i].After(cutoff) {
+		i++
+	}
+	c.times = append(c.times[i:], now)
+	if len(c.times) > d.threshold {
+		c.times = c.times[len(c.times)-d.threshold:]
+	}
+
+	if len(c.times) < d.threshold {
+		c.spiking = false
+		return len(c.times), false
+	}
+	if c.spiking {
+		return len(c.times), false
+	}
+
+	c.spiking = true
+	return len(c.times), true
+}
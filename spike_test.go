@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpikeDetector(t *testing.T) {
+	type spike struct {
+		code  string
+		count int
+	}
+
+	var spikes []spike
+	d := NewSpikeDetector(time.Minute, 3, func(code string, count int, window time.Duration) {
+		spikes = append(spikes, spike{code, count})
+	})
+
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	observe := func(after time.Duration, err error) {
+		now = now.Add(after)
+		d.Report(context.Background(), err)
+	}
+
+	observe(0, NewCode("test.spike"))
+	observe(time.Second, NewCode("test.spike"))
+	observe(time.Second, New("plain"))
+	observe(time.Second, NewCode("test.other"))
+	if len(spikes) != 0 {
+		t.Fatalf("before threshold: got spikes %v", spikes)
+	}
+
+	observe(time.Second, Wrap(NewCode("test.spike"), "call"))
+	observe(time.Second, NewCode("test.spike"))
+	if want := []spike{{"test.spike", 3}}; len(spikes) != 1 || spikes[0] != want[0] {
+		t.Fatalf("at threshold: got spikes %v, want %v", spikes, want)
+	}
+
+	observe(2*time.Minute, NewCode("test.spike"))
+	observe(time.Second, NewCode("test.spike"))
+	observe(time.Second, NewCode("test.spike"))
+	if len(spikes) != 2 {
+		t.Errorf("after window: got spikes %v, want a second spike", spikes)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"path"
-	"runtime"
 	"strconv"
 	"strings"
 )
@@ -20,34 +19,14 @@ func (f Frame) pc() uintptr { return uintptr(f) - 1 }
 
 // file returns the full path to the file that contains the
 // function for this Frame's pc.
-func (f Frame) file() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return "unknown"
-	}
-	file, _ := fn.FileLine(f.pc())
-	return file
-}
+func (f Frame) file() string { return f.resolve().file }
 
 // line returns the line number of source code of the
 // function for this Frame's pc.
-func (f Frame) line() int {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return 0
-	}
-	_, line := fn.FileLine(f.pc())
-	return line
-}
+func (f Frame) line() int { return f.resolve().line }
 
 // name returns the name of this function, if known.
-func (f Frame) name() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return "unknown"
-	}
-	return fn.Name()
-}
+func (f Frame) name() string { return f.resolve().name }
 
 // Format formats the frame according to the fmt.Formatter interface.
 //
@@ -66,9 +45,10 @@ func (f Frame) Format(s fmt.State, verb rune) {
 	case 's':
 		switch {
 		case s.Flag('+'):
-			io.WriteString(s, f.name())
+			info := f.resolve()
+			io.WriteString(s, info.name)
 			io.WriteString(s, "\n\t")
-			io.WriteString(s, f.file())
+			io.WriteString(s, info.file)
 		default:
 			io.WriteString(s, path.Base(f.file()))
 		}
@@ -86,11 +66,11 @@ func (f Frame) Format(s fmt.State, verb rune) {
 // MarshalText formats a stacktrace Frame as a text string. The output is the
 // same as that of fmt.Sprintf("%+v", f), but without newlines or tabs.
 func (f Frame) MarshalText() ([]byte, error) {
-	name := f.name()
-	if name == "unknown" {
-		return []byte(name), nil
+	info := f.resolve()
+	if info.name == "unknown" {
+		return []byte(info.name), nil
 	}
-	return []byte(fmt.Sprintf("%s %s:%d", name, f.file(), f.line())), nil
+	return []byte(fmt.Sprintf("%s %s:%d", info.name, info.file, info.line)), nil
 }
 
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
@@ -163,7 +143,7 @@ func (s *stack) StackTrace() StackTrace {
 func callers() *stack {
 	const depth = 32
 	var pcs [depth]uintptr
-	n := runtime.Callers(3, pcs[:])
+	n := providerCallers(2, pcs[:])
 	var st stack = pcs[0:n]
 	return &st
 }
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// StackProvider captures the stack traces recorded by the errors of this
+// package. Callers fills pcs with the return program counters of the
+// calling goroutine's stack, as runtime.Callers does, and returns the number
+// of entries written. The first skip frames are omitted, 0 identifying the
+// caller of Callers.
+//
+// Alternative providers can capture stacks another way, for example from
+// github.com/go-stack/stack, alongside pprof labels, or not at all:
+//
+//     errors.SetStackProvider(errors.StackProviderFunc(func(int, []uintptr) int {
+//             return 0
+//     }))
+type StackProvider interface {
+	Callers(skip int, pcs []uintptr) int
+}
+
+// StackProviderFunc adapts a function to a StackProvider. The function is
+// given skip as Callers is, 0 identifying the caller of the function.
+type StackProviderFunc func(skip int, pcs []uintptr) int
+
+// Callers calls f, skipping the frame of Callers itself.
+func (f StackProviderFunc) Callers(skip int, pcs []uintptr) int { return f(skip+1, pcs) }
+
+// runtimeStack is the default StackProvider, based on runtime.Callers.
+type runtimeStack struct{}
+
+func (runtimeStack) Callers(skip int, pcs []uintptr) int {
+	// Skip runtime.Callers and this method.
+	return runtime.Callers(skip+2, pcs)
+}
+
+// stackProviderHolder holds the StackProvider in stackProviders, which
+// requires values of a single concrete type.
+type stackProviderHolder struct {
+	p StackProvider
+}
+
+var stackProviders atomic.Value
+
+// SetStackProvider sets the StackProvider capturing the stack traces of the
+// errors created afterwards. A nil StackProvider restores the default,
+// which uses runtime.Callers.
+func SetStackProvider(p StackProvider) {
+	stackProviders.Store(stackProviderHolder{p})
+}
+
+// stackProvider returns the StackProvider set by SetStackProvider, or the
+// default one.
+func stackProvider() StackProvider {
+	if h, _ := stackProviders.Load().(stackProviderHolder); h.p != nil {
+		return h.p
+	}
+
+	return runtimeStack{}
+}
+
+// providerCallers calls the Callers method of the current StackProvider,
+// skipping the frame of providerCallers itself, and bounds the result by
+// the length of pcs.
+func providerCallers(skip int, pcs []uintptr) int {
+	n := stackProvider().Callers(skip+1, pcs)
+	if n < 0 {
+		return 0
+	}
+	if n > len(pcs) {
+		return len(pcs)
+	}
+
+	return n
+}
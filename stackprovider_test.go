@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSetStackProvider(t *testing.T) {
+	defer SetStackProvider(nil)
+
+	SetStackProvider(StackProviderFunc(func(int, []uintptr) int { return 0 }))
+	if st := New("test").(*fundamental).StackTrace(); len(st) != 0 {
+		t.Errorf("no-op provider: got %d frames, want 0", len(st))
+	}
+
+	var skips []int
+	SetStackProvider(StackProviderFunc(func(skip int, pcs []uintptr) int {
+		skips = append(skips, skip)
+		return runtime.Callers(skip+2, pcs)
+	}))
+	st := NewCode("test.stack").(*Error).StackTrace()
+	if len(skips) != 1 {
+		t.Fatalf("provider: called %d times, want 1", len(skips))
+	}
+	if got, want := st[0].name(), "github.com/pkg/errors.TestSetStackProvider"; got != want {
+		t.Errorf("provider: first frame: got %s, want %s", got, want)
+	}
+
+	SetStackProvider(nil)
+	st = NewCode("test.stack").(*Error).StackTrace()
+	if got, want := st[0].name(), "github.com/pkg/errors.TestSetStackProvider"; got != want {
+		t.Errorf("default provider: first frame: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// CollectStats enables the per code statistics returned by Stats. It is
+// disabled by default to keep error construction free of locking, and
+// should be set during program initialization.
+var CollectStats = false
+
+// CodeStats are the statistics of an error code since the process started,
+// or since ResetStats was called.
+type CodeStats struct {
+	// Created counts the coded errors created without a cause.
+	Created uint64 `json:"created"`
+
+	// Wrapped counts the coded errors created wrapping another error.
+	Wrapped uint64 `json:"wrapped"`
+
+	// LastSeen is when the last coded error was created.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+var stats = map[string]*CodeStats{}
+var statsMux = &sync.Mutex{}
+
+// Stats returns a snapshot of the statistics of each code for which a coded
+// error was created while CollectStats was enabled.
+func Stats() map[string]CodeStats {
+	statsMux.Lock()
+	defer statsMux.Unlock()
+
+	snapshot := make(map[string]CodeStats, len(stats))
+	for code, s := range stats {
+		snapshot[code] = *s
+	}
+
+	return snapshot
+}
+
+// ResetStats discards the collected statistics.
+func ResetStats() {
+	statsMux.Lock()
+	defer statsMux.Unlock()
+
+	stats = map[string]*CodeStats{}
+}
+
+// countCode records the creation of a coded error wrapping cause, if
+// CollectStats is enabled.
+func countCode(code string, cause error) {
+	if !CollectStats {
+		return
+	}
+
+	now := time.Now()
+
+	statsMux.Lock()
+	defer statsMux.Unlock()
+
+	s := stats[code]
+	if s == nil {
+		s = &CodeStats{}
+		stats[code] = s
+	}
+
+	if cause == nil {
+		s.Created++
+	} else {
+		s.Wrapped++
+	}
+	s.LastSeen = now
+}
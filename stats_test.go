@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	NewCode("test.stats.disabled")
+	if _, ok := Stats()["test.stats.disabled"]; ok {
+		t.Errorf("Stats: counted test.stats.disabled while disabled")
+	}
+
+	CollectStats = true
+	defer func() { CollectStats = false }()
+	defer ResetStats()
+
+	before := time.Now()
+	NewCode("test.stats")
+	NewCodeWithParams("test.stats", nil)
+	WrapCode(io.EOF, "test.stats")
+	WrapCode(nil, "test.stats")
+
+	got := Stats()["test.stats"]
+	if got.Created != 2 || got.Wrapped != 1 {
+		t.Errorf("Stats: got created %d and wrapped %d, want 2 and 1", got.Created, got.Wrapped)
+	}
+	if got.LastSeen.Before(before) {
+		t.Errorf("Stats: got last seen %v, want after %v", got.LastSeen, before)
+	}
+
+	ResetStats()
+	if got := Stats(); len(got) != 0 {
+		t.Errorf("ResetStats: got %v, want empty", got)
+	}
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// UnregisteredCode is the code of the errors inserted by StrictReplace.
+const UnregisteredCode = "unregistered_code"
+
+// StrictMode selects how the constructors of this package treat codes that
+// are not registered.
+type StrictMode int32
+
+const (
+	// StrictOff accepts unregistered codes. It is the default.
+	StrictOff StrictMode = iota
+
+	// StrictReplace inserts an UnregisteredCode error, with the unregistered
+	// code in its "code" param, as the cause of errors created with an
+	// unregistered code, and a RetiredCode error likewise for errors
+	// created with a retired code. The original cause, if any, is wrapped
+	// by it.
+	StrictReplace
+
+	// StrictPanic panics when an error is created with an unregistered or
+	// retired code. It is meant for tests, to surface drift between code
+	// and catalog early.
+	StrictPanic
+)
+
+var strictMode int32
+
+func init() {
+	switch os.Getenv("ERRORS_STRICT_CODES") {
+	case "replace":
+		SetStrictCodes(StrictReplace)
+	case "panic":
+		SetStrictCodes(StrictPanic)
+	}
+}
+
+// SetStrictCodes sets how the constructors of this package, such as NewCode
+// and WrapCode, treat codes that are not registered or are retired.
+// FromCoder and the decoders of serialized errors are not affected.
+// The mode can also be set with the ERRORS_STRICT_CODES environment
+// variable, to "replace" or "panic".
+func SetStrictCodes(mode StrictMode) {
+	atomic.StoreInt32(&strictMode, int32(mode))
+}
+
+// checkCode records the creation of a coded error with code wrapping cause,
+// and returns the cause to use for it according to the strict mode.
+func checkCode(code string, cause error) error {
+	countCode(code, cause)
+
+	mode := StrictMode(atomic.LoadInt32(&strictMode))
+	if mode == StrictOff {
+		return cause
+	}
+
+	replacement, problem := UnregisteredCode, "is not registered"
+	if coder := GetCoder(code); coder != nil {
+		if lifecycleOf(coder) != LifecycleRetired {
+			return cause
+		}
+		replacement, problem = RetiredCode, "is retired"
+	}
+
+	if mode == StrictPanic {
+		panic(fmt.Sprintf("code: %s %s", code, problem))
+	}
+
+	return &Error{
+		code:    replacement,
+		message: "code " + code + " " + problem,
+		params:  map[string]interface{}{"code": code},
+		cause:   cause,
+		id:      errorID(cause),
+		stack:   &stack{},
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestStrictCodes(t *testing.T) {
+	Register(testCoder{code: "test.strict", message: "registered"})
+	defer SetStrictCodes(StrictOff)
+
+	SetStrictCodes(StrictReplace)
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewCode("test.strict"), "test.strict - registered"},
+		{NewCode("test.strict.unknown", "oops"), "test.strict.unknown - oops: unregistered_code - code test.strict.unknown is not registered"},
+		{WrapCode(io.EOF, "test.strict.unknown", "oops"), "test.strict.unknown - oops: unregistered_code - code test.strict.unknown is not registered: EOF"},
+		{WrapCode(io.EOF, "test.strict"), "test.strict - registered: EOF"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+	if err := tests[2].err; !HasCode(err, UnregisteredCode) || RootCause(err) != io.EOF {
+		t.Errorf("StrictReplace: got %v, want %s wrapping EOF", err, UnregisteredCode)
+	}
+
+	SetStrictCodes(StrictPanic)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("StrictPanic: NewCode with an unregistered code did not panic")
+			}
+		}()
+		NewCode("test.strict.unknown")
+	}()
+	NewCode("test.strict")
+
+	SetStrictCodes(StrictOff)
+	if got, want := NewCode("test.strict.unknown", "oops").Error(), "test.strict.unknown - oops"; got != want {
+		t.Errorf("StrictOff: got %q, want %q", got, want)
+	}
+}
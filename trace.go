@@ -0,0 +1,54 @@
+package errors
+
+import "context"
+
+// TraceIDs, if set, returns the trace and span IDs of the span active in ctx.
+// It is used by WrapCodeCtx to correlate errors with traces. Building with
+// the otel tag sets it to read OpenTelemetry span contexts.
+var TraceIDs func(ctx context.Context) (traceID, spanID string)
+
+// WrapCodeCtx returns an error annotating err with a stack trace
+// at the point WrapCodeCtx is called, and the supplied code and message,
+// as WrapCode does. The trace and span IDs active in ctx, if any, are
+// recorded in the "trace_id" and "span_id" params, along with the params
+// added to ctx by Decorate, and the stack captured by CaptureSpawnStack,
+// if any, is recorded as the spawn stack.
+// If err is nil, WrapCodeCtx returns nil.
+func WrapCodeCtx(ctx context.Context, err error, code string, msgs ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	err = checkCode(code, err)
+
+	return &Error{
+		code:    code,
+		message: explicitMessage(msgs),
+		source:  messageSourceOf(msgs),
+		params:  ctxParams(ctx, nil),
+		cause:   err,
+		id:      errorID(err),
+		spawn:   spawnStack(ctx),
+		seq:     recordWrap(code),
+		stack:   callers(),
+	}
+}
+
+// traceParams returns the trace params for ctx, or nil if there is no trace.
+func traceParams(ctx context.Context) map[string]interface{} {
+	if TraceIDs == nil || ctx == nil {
+		return nil
+	}
+
+	traceID, spanID := TraceIDs(ctx)
+	if traceID == "" {
+		return nil
+	}
+
+	params := map[string]interface{}{"trace_id": traceID}
+	if spanID != "" {
+		params["span_id"] = spanID
+	}
+
+	return params
+}
@@ -0,0 +1,20 @@
+// +build otel
+
+package errors
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	TraceIDs = func(ctx context.Context) (string, string) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			return "", ""
+		}
+
+		return sc.TraceID().String(), sc.SpanID().String()
+	}
+}
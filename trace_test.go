@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type traceKey struct{}
+
+func TestWrapCodeCtx(t *testing.T) {
+	saved := TraceIDs
+	TraceIDs = func(ctx context.Context) (string, string) {
+		ids, _ := ctx.Value(traceKey{}).([2]string)
+		return ids[0], ids[1]
+	}
+	defer func() { TraceIDs = saved }()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, [2]string{"t-1", "s-1"})
+	err := WrapCodeCtx(ctx, New("root"), "test.trace", "traced")
+	want := map[string]interface{}{"trace_id": "t-1", "span_id": "s-1"}
+	if got := Params(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params: got %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "test.trace - traced: root"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+
+	err = WrapCodeCtx(context.Background(), New("root"), "test.trace")
+	if got := Params(err); got != nil {
+		t.Errorf("Params without trace: got %v, want nil", got)
+	}
+
+	if WrapCodeCtx(ctx, nil, "test.trace") != nil {
+		t.Errorf("WrapCodeCtx(nil): want nil")
+	}
+}
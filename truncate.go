@@ -0,0 +1,78 @@
+package errors
+
+// truncated marks where Truncate cut an error chain.
+var truncated = &fundamental{msg: "...", stack: &stack{}}
+
+// Truncate returns a copy of err keeping only its outermost depth layers,
+// so that errors built by deep call stacks do not leak internals or bloat
+// responses. A layer is a coded error, a message added by Wrap or
+// WithMessage, or an error not created by this package, which is kept with
+// everything it wraps. The cut is marked by an error with the text "...".
+// A depth less than 1 is treated as 1.
+// If err has no more than depth layers, it is returned unchanged.
+func Truncate(err error, depth int) error {
+	if depth < 1 {
+		depth = 1
+	}
+
+	if t, ok := truncateChain(err, depth, 0); ok {
+		return t
+	}
+
+	return err
+}
+
+// truncateChain returns a copy of err keeping n layers, and reports whether
+// any layer was cut.
+func truncateChain(err error, n, depth int) (error, bool) {
+	if err == nil || depth >= MaxChainDepth {
+		return nil, false
+	}
+
+	switch e := err.(type) {
+	case *withStack:
+		if inner, ok := truncateChain(e.error, n, depth+1); ok {
+			return &withStack{inner, e.stack}, true
+		}
+		return nil, false
+	case *withRetryAfter:
+		if inner, ok := truncateChain(e.error, n, depth+1); ok {
+			return &withRetryAfter{inner, e.after}, true
+		}
+		return nil, false
+	case *withUpstream:
+		if inner, ok := truncateChain(e.error, n, depth+1); ok {
+			return &withUpstream{inner, e.upstream}, true
+		}
+		return nil, false
+	case *withoutCause:
+		if inner, ok := truncateChain(e.error, n, depth+1); ok {
+			return &withoutCause{inner}, true
+		}
+		return nil, false
+	case *withFrameNote:
+		if inner, ok := truncateChain(e.error, n, depth+1); ok {
+			return &withFrameNote{inner, e.frame, e.note}, true
+		}
+		return nil, false
+	}
+
+	if n == 0 {
+		return truncated, true
+	}
+
+	switch e := err.(type) {
+	case *Error:
+		if inner, ok := truncateChain(e.cause, n-1, depth+1); ok {
+			wc := e.clone()
+			wc.cause = inner
+			return &wc, true
+		}
+	case *withMessage:
+		if inner, ok := truncateChain(e.cause, n-1, depth+1); ok {
+			return &withMessage{cause: inner, msg: e.msg}, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestTruncateLayers(t *testing.T) {
+	err := WrapCode(Wrap(WrapCode(io.EOF, "test.inner", "read"), "load"), "test.outer", "request")
+
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		{0, "test.outer - request: ..."},
+		{1, "test.outer - request: ..."},
+		{2, "test.outer - request: load: ..."},
+		{3, "test.outer - request: load: test.inner - read: ..."},
+		{4, "test.outer - request: load: test.inner - read: EOF"},
+		{5, "test.outer - request: load: test.inner - read: EOF"},
+	}
+
+	for _, tt := range tests {
+		got := Truncate(err, tt.depth)
+		if got.Error() != tt.want {
+			t.Errorf("Truncate(%d): got %q, want %q", tt.depth, got.Error(), tt.want)
+		}
+	}
+
+	if got := Truncate(err, 4); got != err {
+		t.Errorf("Truncate(4): got a copy, want err unchanged")
+	}
+	if got := FirstCode(Truncate(err, 1)); got != "test.outer" {
+		t.Errorf("FirstCode: got %q, want %q", got, "test.outer")
+	}
+	if got := Truncate(nil, 1); got != nil {
+		t.Errorf("Truncate(nil): got %v, want nil", got)
+	}
+}
@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Upstream describes the external system an error originates from: the
+// service that was called, the endpoint of the call, such as a URL path or
+// RPC method, and the status it returned, such as an HTTP status code.
+// A zero Status means the call returned no status, for example because the
+// connection failed.
+type Upstream struct {
+	Service  string `json:"service" xml:"service"`
+	Endpoint string `json:"endpoint,omitempty" xml:"endpoint,omitempty"`
+	Status   int    `json:"status,omitempty" xml:"status,omitempty"`
+}
+
+// WithUpstream annotates err with the upstream call it originates from, so
+// that the provenance of the error is machine readable instead of being
+// part of its message:
+//
+//     resp, err := client.Do(req)
+//     if err == nil && resp.StatusCode >= 500 {
+//             err = errors.NewCode("billing.unavailable")
+//     }
+//     if err != nil {
+//             return errors.WithUpstream(err, "billing", "POST /v1/charges", status(resp))
+//     }
+//
+// The annotation is encoded in the upstream section of the layer of err in
+// the wire format. It does not change err's message.
+// If err is nil, WithUpstream returns nil.
+func WithUpstream(err error, service, endpoint string, status int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withUpstream{
+		error:    err,
+		upstream: Upstream{Service: service, Endpoint: endpoint, Status: status},
+	}
+}
+
+type withUpstream struct {
+	error
+	upstream Upstream
+}
+
+func (w *withUpstream) Cause() error { return w.error }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withUpstream) Unwrap() error { return w.error }
+
+func (w *withUpstream) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.Cause())
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// UpstreamOf returns the outermost WithUpstream annotation of err's chain.
+// The boolean reports whether one was found.
+func UpstreamOf(err error) (Upstream, bool) {
+	var upstream Upstream
+	found := false
+	walk(err, func(err error) bool {
+		if e, ok := err.(*withUpstream); ok {
+			upstream, found = e.upstream, true
+		}
+
+		return !found
+	})
+
+	return upstream, found
+}
@@ -0,0 +1,62 @@
+package errors
+
+import "testing"
+
+func TestWithUpstream(t *testing.T) {
+	if err := WithUpstream(nil, "billing", "POST /v1/charges", 502); err != nil {
+		t.Errorf("WithUpstream(nil): got %v, want nil", err)
+	}
+
+	err := Wrap(WithUpstream(NewCode("test.upstream", "charge failed"), "billing", "POST /v1/charges", 502), "checkout")
+	if got, want := err.Error(), "checkout: test.upstream - charge failed"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+
+	want := Upstream{Service: "billing", Endpoint: "POST /v1/charges", Status: 502}
+	if got, ok := UpstreamOf(err); !ok || got != want {
+		t.Errorf("UpstreamOf: got %+v, %v, want %+v, true", got, ok, want)
+	}
+	if _, ok := UpstreamOf(NewCode("test.upstream")); ok {
+		t.Error("UpstreamOf: got true without an annotation, want false")
+	}
+}
+
+func TestWireUpstream(t *testing.T) {
+	err := WithUpstream(Wrap(WithUpstream(New("EOF"), "ledger", "", 0), "read"), "billing", "POST /v1/charges", 502)
+
+	data, perr := ToJSON(err)
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	want := `{"schema_version":1,"chain":[` +
+		`{"message":"read","upstream":{"service":"billing","endpoint":"POST /v1/charges","status":502}},` +
+		`{"message":"EOF","upstream":{"service":"ledger"}}]}`
+	if string(data) != want {
+		t.Errorf("ToJSON: got %s, want %s", data, want)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		decode func() (error, error)
+	}{
+		{"FromJSON", func() (error, error) { return FromJSON(data) }},
+		{"FromXML", func() (error, error) {
+			x, err := ToXML(err)
+			if err != nil {
+				return nil, err
+			}
+			return FromXML(x)
+		}},
+	} {
+		got, perr := tt.decode()
+		if perr != nil {
+			t.Fatalf("%s: %v", tt.name, perr)
+		}
+		if !EquivalentForTest(got, err) {
+			t.Errorf("%s: got %v, not equivalent to %v", tt.name, got, err)
+		}
+		if b, _ := ToJSON(got); string(b) != want {
+			t.Errorf("%s: got %s, want %s", tt.name, b, want)
+		}
+	}
+}
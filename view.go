@@ -0,0 +1,69 @@
+package errors
+
+// View selects which fields of an error are exposed when it is serialized.
+// Every serializer of this package, such as WriteHTTP, ToJSON or ToGraphQL,
+// emits a view when given the result of View.Apply:
+//
+//     errors.WriteHTTP(w, errors.ViewPublic.Apply(err))
+type View int
+
+const (
+	// ViewInternal exposes everything: the whole chain with params, IDs,
+	// causes and stack traces. It is meant for logs and trusted services.
+	ViewInternal View = iota
+
+	// ViewPublic exposes only the code, the user facing message and the
+	// reference of the registered Coder. It is meant for end users.
+	ViewPublic
+)
+
+// Apply returns the view v of err.
+func (v View) Apply(err error) error {
+	if v == ViewPublic {
+		return Public(err)
+	}
+
+	return Internal(err)
+}
+
+// publicMessage is the message of the public view of an error without code.
+const publicMessage = "internal error"
+
+// Public returns the public view of err: an error holding only the code of
+// the outermost coded error in err's chain and its registered Coder, which
+// provides the user facing message, the status code and the reference.
+// The message the error was created with is meant for developers and is
+// not included, nor are params, IDs, causes and stack traces.
+// If the code is not registered, the view has the message "internal error",
+// as it has if err has no coded error.
+// If err is nil, Public returns nil.
+func Public(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	wc := codeOf(err)
+	if wc == nil {
+		return &fundamental{msg: publicMessage, stack: &stack{}}
+	}
+
+	coder := wc.Coder()
+	msg := publicMessage
+	if coder != nil {
+		msg = coder.Message()
+	}
+
+	return &Error{
+		code:    wc.code,
+		message: msg,
+		coder:   coder,
+		stack:   &stack{},
+	}
+}
+
+// Internal returns the internal view of err, which is err itself with all
+// of its fields. It exists so that the boundary between what is logged and
+// what is shown to users is spelled out where errors are serialized.
+func Internal(err error) error {
+	return err
+}
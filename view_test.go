@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublic(t *testing.T) {
+	Register(testCoder{code: "test.view", status: http.StatusConflict, message: "already exists"})
+
+	err := Wrap(WrapCodeWithParams(io.EOF, "test.view", map[string]interface{}{"table": "users"}), "insert")
+
+	public := Public(err)
+	if got, want := public.Error(), "test.view - already exists"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", public), "test.view - already exists"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if got := Params(public); got != nil {
+		t.Errorf("Params(): got %v, want nil", got)
+	}
+	if got := ParseCoder(public); got == nil || got.StatusCode() != http.StatusConflict {
+		t.Errorf("ParseCoder(): got %v, want status %d", got, http.StatusConflict)
+	}
+
+	internal := WrapCode(io.EOF, "test.view", "internal detail host=10.0.0.1")
+	if got, want := Public(internal).Error(), "test.view - already exists"; got != want {
+		t.Errorf("Public(internal message): got %q, want %q", got, want)
+	}
+	if got, want := Public(NewCode("test.view.unregistered", "internal detail")).Error(), "test.view.unregistered - internal error"; got != want {
+		t.Errorf("Public(unregistered): got %q, want %q", got, want)
+	}
+
+	if got, want := Public(io.EOF).Error(), "internal error"; got != want {
+		t.Errorf("Public(uncoded): got %q, want %q", got, want)
+	}
+	if Public(nil) != nil {
+		t.Errorf("Public(nil): got non-nil")
+	}
+}
+
+func TestViewApply(t *testing.T) {
+	err := NewCodeWithParams("test.view.apply", map[string]interface{}{"id": 7}, "not found")
+
+	if got := ViewInternal.Apply(err); got != err {
+		t.Errorf("ViewInternal.Apply: got %v, want %v", got, err)
+	}
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, ViewPublic.Apply(err))
+	if got, want := w.Body.String(), "{\"code\":\"test.view.apply\",\"message\":\"internal error\"}\n"; got != want {
+		t.Errorf("WriteHTTP(public): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,382 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// MaxWireBytes is the maximum size of an encoded error accepted by FromJSON
+// and the binary decoders.
+const MaxWireBytes = 1 << 20
+
+// WireSchemaVersion is the version of the wire format written by ToWire.
+// Decoders accept payloads of any version and ignore fields they do not
+// know, so that the format can gain fields without breaking old readers.
+const WireSchemaVersion = 1
+
+// maxWireParams is the maximum number of params of a layer accepted by FromWire.
+const maxWireParams = 256
+
+// Wire is the wire format of an error chain, shared by ToJSON and the
+// binary encodings. The layers of the chain are listed outermost first;
+// the last layer is the root cause.
+type Wire struct {
+	SchemaVersion int         `json:"schema_version"`
+	Chain         []WireLayer `json:"chain"`
+}
+
+// WireLayer is a single error of a chain in the wire format. Coded errors
+// carry a code, message annotations and root causes only a message.
+//
+// ParamTypes holds the Go type of the params whose type would otherwise be
+// lost in encodings such as JSON: integers of any size, time.Time and
+// time.Duration. FromWire uses it to restore the original param values.
+//
+// NumCode is the numeric code of the registered Coder of a coded layer, if
+// it has one, for consumers that need integer codes. FromWire ignores it, as
+// codes are looked up by their string code.
+//
+// Wrapped is set for coded errors created by NewCodef whose message includes
+// the text of their cause. Upstream holds the WithUpstream annotation of the
+// layer, if any.
+type WireLayer struct {
+	Code       string                 `json:"code,omitempty"`
+	NumCode    *int                   `json:"num_code,omitempty"`
+	Message    string                 `json:"message"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	ParamTypes map[string]string      `json:"param_types,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Wrapped    bool                   `json:"wrapped,omitempty"`
+	Upstream   *Upstream              `json:"upstream,omitempty"`
+}
+
+// ToJSON encodes err's chain, as returned by ToWire, in the JSON wire format
+// understood by FromJSON. Stack traces are not encoded.
+// If err is nil, ToJSON returns the JSON null value.
+func ToJSON(err error) ([]byte, error) {
+	return json.Marshal(ToWire(err))
+}
+
+// ToWire returns the wire format of err's chain. Coded errors keep their
+// code, numeric code, message, params and instance ID; other errors keep
+// their message. WithUpstream annotations are kept in the layer of the
+// error they annotate. The chain of an error returned by WithoutCauseInError
+// ends with its outermost coded error, as its Error text does.
+// The serialization limits are applied. If err is nil, ToWire returns nil.
+func ToWire(err error) *Wire {
+	if err == nil {
+		return nil
+	}
+
+	l := serializationLimits()
+	w := &Wire{SchemaVersion: WireSchemaVersion}
+	limited := false
+	var upstream *Upstream
+	stopAtCode := false
+	truncated := walk(err, func(err error) bool {
+		if l.MaxDepth > 0 && len(w.Chain) >= l.MaxDepth {
+			limited = true
+			return false
+		}
+
+		if wc, ok := asCode(err); ok {
+			err = wc
+		}
+
+		switch e := err.(type) {
+		case *Error:
+			params := l.limitParams(e.params)
+			var num *int
+			if n, ok := numCode(e); ok {
+				num = &n
+			}
+			w.Chain = append(w.Chain, WireLayer{
+				Code:       e.code,
+				NumCode:    num,
+				Message:    l.limitMessage(e.Message()),
+				Params:     params,
+				ParamTypes: paramTypes(params),
+				ID:         e.id,
+				Wrapped:    e.wrapped && e.cause != nil && !stopAtCode,
+				Upstream:   upstream,
+			})
+			if stopAtCode {
+				return false
+			}
+		case *withMessage:
+			w.Chain = append(w.Chain, WireLayer{Message: l.limitMessage(e.msg), Upstream: upstream})
+		case *withoutCause:
+			// The causes of the outermost coded error are left out, as
+			// they are from the Error text.
+			stopAtCode = stopAtCode || codeOf(e.error) != nil
+			return true
+		case *withUpstream:
+			if upstream == nil {
+				u := e.upstream
+				upstream = &u
+			}
+			return true
+		case *withStack, *withRetryAfter, *withFrameNote:
+			return true
+		default:
+			w.Chain = append(w.Chain, WireLayer{Message: l.limitMessage(err.Error()), Upstream: upstream})
+			return false
+		}
+		upstream = nil
+
+		return true
+	})
+	if truncated || limited {
+		w.Chain = append(w.Chain, WireLayer{Message: "..."})
+	}
+
+	return w
+}
+
+// FromJSON decodes an error chain encoded by ToJSON. Coded layers become
+// coded errors and the other layers message annotations, so the decoded
+// error has the same Error text, codes and params as the encoded one.
+// Integer, time.Time and time.Duration params are restored with their
+// original type; other numbers are decoded as float64.
+// The stack trace of the decoded errors is recorded at the point FromJSON
+// is called. The JSON null value decodes to a nil error.
+//
+// FromJSON is safe to use on payloads from untrusted sources: payloads
+// larger than 1MiB, with more than MaxChainDepth layers or with more than
+// 256 params in a layer are rejected.
+func FromJSON(data []byte) (error, error) {
+	if len(data) > MaxWireBytes {
+		return nil, Errorf("errors: wire payload of %d bytes exceeds %d bytes", len(data), MaxWireBytes)
+	}
+
+	var w *Wire
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&w); err != nil {
+		return nil, Wrap(err, "errors: invalid wire payload")
+	}
+
+	return FromWire(w)
+}
+
+// FromWire decodes an error chain from its wire format, as FromJSON does.
+// If w is nil, FromWire returns a nil error.
+func FromWire(w *Wire) (error, error) {
+	if w == nil {
+		return nil, nil
+	}
+	if len(w.Chain) == 0 {
+		return nil, New("errors: wire payload has an empty chain")
+	}
+	if len(w.Chain) > MaxChainDepth {
+		return nil, Errorf("errors: wire chain of %d layers exceeds %d layers", len(w.Chain), MaxChainDepth)
+	}
+
+	var err error
+	for i := len(w.Chain) - 1; i >= 0; i-- {
+		l := w.Chain[i]
+		if len(l.Params) > maxWireParams {
+			return nil, Errorf("errors: wire layer has %d params, more than %d", len(l.Params), maxWireParams)
+		}
+
+		switch {
+		case l.Code != "":
+			err = &Error{
+				code:    l.Code,
+				message: l.Message,
+				params:  typedParams(l.Params, l.ParamTypes),
+				cause:   err,
+				id:      l.ID,
+				wrapped: l.Wrapped && err != nil,
+				stack:   callers(),
+			}
+		case err == nil:
+			err = &fundamental{
+				msg:   l.Message,
+				stack: callers(),
+			}
+		default:
+			err = &withMessage{
+				cause: err,
+				msg:   l.Message,
+			}
+		}
+		if l.Upstream != nil {
+			err = &withUpstream{error: err, upstream: *l.Upstream}
+		}
+	}
+
+	return err, nil
+}
+
+// paramTypes returns the ParamTypes of a WireLayer with params.
+func paramTypes(params map[string]interface{}) map[string]string {
+	var types map[string]string
+	for k, v := range params {
+		t := ""
+		switch v.(type) {
+		case int:
+			t = "int"
+		case int8:
+			t = "int8"
+		case int16:
+			t = "int16"
+		case int32:
+			t = "int32"
+		case int64:
+			t = "int64"
+		case uint:
+			t = "uint"
+		case uint8:
+			t = "uint8"
+		case uint16:
+			t = "uint16"
+		case uint32:
+			t = "uint32"
+		case uint64:
+			t = "uint64"
+		case time.Duration:
+			t = "duration"
+		case time.Time:
+			t = "time"
+		default:
+			continue
+		}
+
+		if types == nil {
+			types = map[string]string{}
+		}
+		types[k] = t
+	}
+
+	return types
+}
+
+// typedParams returns params with the values listed in types converted back
+// to their Go type. Values that cannot be converted are kept as decoded.
+func typedParams(params map[string]interface{}, types map[string]string) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+
+	typed := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if t, ok := types[k]; ok {
+			if tv, ok := typedParam(t, v); ok {
+				typed[k] = tv
+				continue
+			}
+		}
+		typed[k] = untypedParam(v)
+	}
+
+	return typed
+}
+
+// typedParam converts a decoded param value to the Go type named t.
+func typedParam(t string, v interface{}) (interface{}, bool) {
+	if t == "time" {
+		switch v := v.(type) {
+		case time.Time:
+			return v, true
+		case string:
+			tv, err := time.Parse(time.RFC3339Nano, v)
+			return tv, err == nil
+		}
+		return nil, false
+	}
+
+	// Decoders return numbers as json.Number, float64 or an integer of any
+	// size; they are converted through their decimal text so that integers
+	// beyond the precision of float64 are kept intact.
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int:
+		s = strconv.FormatInt(int64(v), 10)
+	case int8:
+		s = strconv.FormatInt(int64(v), 10)
+	case int16:
+		s = strconv.FormatInt(int64(v), 10)
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	default:
+		return nil, false
+	}
+
+	// Values out of the range of t are left untyped rather than truncated.
+	switch t {
+	case "uint":
+		n, err := strconv.ParseUint(s, 10, strconv.IntSize)
+		return uint(n), err == nil
+	case "uint8":
+		n, err := strconv.ParseUint(s, 10, 8)
+		return uint8(n), err == nil
+	case "uint16":
+		n, err := strconv.ParseUint(s, 10, 16)
+		return uint16(n), err == nil
+	case "uint32":
+		n, err := strconv.ParseUint(s, 10, 32)
+		return uint32(n), err == nil
+	case "uint64":
+		n, err := strconv.ParseUint(s, 10, 64)
+		return n, err == nil
+	case "int":
+		n, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		return int(n), err == nil
+	case "int8":
+		n, err := strconv.ParseInt(s, 10, 8)
+		return int8(n), err == nil
+	case "int16":
+		n, err := strconv.ParseInt(s, 10, 16)
+		return int16(n), err == nil
+	case "int32":
+		n, err := strconv.ParseInt(s, 10, 32)
+		return int32(n), err == nil
+	case "int64":
+		n, err := strconv.ParseInt(s, 10, 64)
+		return n, err == nil
+	case "duration":
+		n, err := strconv.ParseInt(s, 10, 64)
+		return time.Duration(n), err == nil
+	}
+	return nil, false
+}
+
+// untypedParam converts the json.Number values of a param decoded by
+// FromJSON to float64, as json.Unmarshal decodes them.
+func untypedParam(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = untypedParam(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = untypedParam(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []error{
+		New("root"),
+		NewCode("test.wire", "coded"),
+		NewCodeWithParams("test.wire", map[string]interface{}{"id": "7"}, "coded"),
+		WrapCode(Wrap(io.EOF, "read"), "test.wire", "outer"),
+		WithMessage(WrapCode(NewCode("test.inner", "inner"), "test.outer", "outer"), "ctx"),
+	}
+
+	for i, want := range tests {
+		data, err := ToJSON(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := FromJSON(data)
+		if err != nil {
+			t.Fatalf("test %d: FromJSON(%s): %v", i+1, data, err)
+		}
+		if got.Error() != want.Error() || FirstCode(got) != FirstCode(want) || RootCode(got) != RootCode(want) {
+			t.Errorf("test %d: round trip of %s: got %q, want %q", i+1, data, got, want)
+		}
+	}
+}
+
+func TestJSONParamTypes(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 5, time.UTC)
+	params := map[string]interface{}{
+		"count":   42,
+		"big":     int64(1<<62 + 1),
+		"size":    uint32(7),
+		"at":      at,
+		"timeout": 1500 * time.Millisecond,
+		"ratio":   0.5,
+		"name":    "x",
+		"nested":  map[string]interface{}{"n": 1},
+	}
+
+	data, err := ToJSON(NewCodeWithParams("test.wire.types", params))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON(%s): %v", data, err)
+	}
+
+	want := map[string]interface{}{
+		"count":   42,
+		"big":     int64(1<<62 + 1),
+		"size":    uint32(7),
+		"at":      at,
+		"timeout": 1500 * time.Millisecond,
+		"ratio":   0.5,
+		"name":    "x",
+		"nested":  map[string]interface{}{"n": 1.0},
+	}
+	if got := Params(got); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip of %s:\n got %#v\n want %#v", data, got, want)
+	}
+}
+
+func TestJSONParamTypesOutOfRange(t *testing.T) {
+	tests := []struct {
+		typ  string
+		n    string
+		want float64
+	}{
+		{"uint8", "300", 300},
+		{"int8", "300", 300},
+		{"int8", "-200", -200},
+		{"uint16", "70000", 70000},
+		{"int16", "70000", 70000},
+		{"uint32", "5000000000", 5000000000},
+		{"int32", "5000000000", 5000000000},
+		{"uint64", "-1", -1},
+		{"int64", "1e30", 1e30},
+	}
+
+	for _, tt := range tests {
+		data := `{"chain":[{"code":"x","message":"m","params":{"n":` + tt.n + `},"param_types":{"n":"` + tt.typ + `"}}]}`
+		got, err := FromJSON([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Params(got)["n"]; got != tt.want {
+			t.Errorf("%s %s: got %#v, want %v", tt.typ, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestJSONNil(t *testing.T) {
+	data, err := ToJSON(nil)
+	if err != nil || string(data) != "null" {
+		t.Errorf("ToJSON(nil): got %s, %v", data, err)
+	}
+
+	got, err := FromJSON(data)
+	if got != nil || err != nil {
+		t.Errorf("FromJSON(null): got %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestFromJSONInvalid(t *testing.T) {
+	tests := []string{
+		``,
+		`{`,
+		`[]`,
+		`{"chain":[]}`,
+		`{"chain":"x"}`,
+		`{"chain":[` + strings.Repeat(`{"message":"x"},`, MaxChainDepth) + `{"message":"x"}]}`,
+		`{"chain":[{"message":"x","params":{` + manyParams(300) + `}}]}`,
+		`{"chain":[{"message":"` + strings.Repeat("x", MaxWireBytes) + `"}]}`,
+	}
+
+	for i, tt := range tests {
+		if got, err := FromJSON([]byte(tt)); err == nil {
+			t.Errorf("test %d: FromJSON: got %v, want error", i+1, got)
+		}
+	}
+}
+
+func manyParams(n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = fmt.Sprintf(`"p%d":%d`, i, i)
+	}
+
+	return strings.Join(params, ",")
+}
+
+func TestFromJSONSchemaVersions(t *testing.T) {
+	tests := []string{
+		`{"chain":[{"code":"test.wire","message":"unversioned"}]}`,
+		`{"schema_version":1,"chain":[{"code":"test.wire","message":"current"}]}`,
+		`{"schema_version":7,"severity":"high","chain":[{"code":"test.wire","message":"future","hint":{"x":1}}]}`,
+	}
+
+	for i, tt := range tests {
+		got, err := FromJSON([]byte(tt))
+		if err != nil {
+			t.Errorf("test %d: FromJSON(%s): %v", i+1, tt, err)
+			continue
+		}
+		if Code(got) != "test.wire" {
+			t.Errorf("test %d: FromJSON(%s): got code %q, want %q", i+1, tt, Code(got), "test.wire")
+		}
+	}
+
+	data, _ := ToJSON(New("root"))
+	if want := `{"schema_version":1,`; !strings.HasPrefix(string(data), want) {
+		t.Errorf("ToJSON: got %s, want prefix %s", data, want)
+	}
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordWraps enables the recording of the construction of coded errors
+// returned by WrapHistory. It is disabled by default, since recording
+// takes a global lock per coded error; building with the errorsdebug tag
+// enables it. It should be set during program initialization.
+var RecordWraps = false
+
+// WrapHistorySize is the number of most recent wrap events kept while
+// RecordWraps is enabled. It is read when the first event is recorded.
+var WrapHistorySize = 1024
+
+// WrapEvent is the construction of a coded error recorded while RecordWraps
+// was enabled.
+type WrapEvent struct {
+	// Code is the code of the constructed error.
+	Code string
+
+	// Frame is the call site of the constructor, such as WrapCode.
+	Frame Frame
+
+	// Time is when the error was constructed.
+	Time time.Time
+}
+
+type wrapRecord struct {
+	seq  uint64
+	code string
+	time time.Time
+}
+
+// wrapRing holds the most recent records, the record of sequence number
+// seq at index (seq-1) % len(records).
+var wrapRing = struct {
+	sync.Mutex
+	records []wrapRecord
+	seq     uint64 // of the last record
+}{}
+
+// recordWrap records the construction of a coded error with code, if
+// RecordWraps is enabled, and returns the sequence number of the record, to
+// be stored in the error. It returns 0 if RecordWraps is disabled.
+func recordWrap(code string) uint64 {
+	if !RecordWraps {
+		return 0
+	}
+
+	now := time.Now()
+
+	wrapRing.Lock()
+	defer wrapRing.Unlock()
+
+	if wrapRing.records == nil {
+		size := WrapHistorySize
+		if size < 1 {
+			size = 1
+		}
+		wrapRing.records = make([]wrapRecord, size)
+	}
+	wrapRing.seq++
+	wrapRing.records[(wrapRing.seq-1)%uint64(len(wrapRing.records))] = wrapRecord{seq: wrapRing.seq, code: code, time: now}
+
+	return wrapRing.seq
+}
+
+// WrapHistory returns the recorded construction of each coded error in
+// err's chain, innermost first, to find out where the codes of an error
+// were added:
+//
+//     for _, e := range errors.WrapHistory(err) {
+//             log.Printf("%s added at %+v at %s", e.Code, e.Frame, e.Time)
+//     }
+//
+// Coded errors whose construction was not recorded, because RecordWraps was
+// disabled or the event was evicted from the history, are omitted.
+func WrapHistory(err error) []WrapEvent {
+	var events []WrapEvent
+	walk(err, func(err error) bool {
+		if wc, ok := asCode(err); ok && wc.seq != 0 {
+			if e, ok := findWrap(wc.seq); ok {
+				if wc.stack != nil && len(*wc.stack) > 0 {
+					e.Frame = Frame((*wc.stack)[0])
+				}
+				events = append(events, e)
+			}
+		}
+
+		return true
+	})
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	return events
+}
+
+// findWrap returns the event recorded with the sequence number seq, unless
+// it has been evicted.
+func findWrap(seq uint64) (WrapEvent, bool) {
+	wrapRing.Lock()
+	defer wrapRing.Unlock()
+
+	if len(wrapRing.records) == 0 {
+		return WrapEvent{}, false
+	}
+
+	r := wrapRing.records[(seq-1)%uint64(len(wrapRing.records))]
+	if r.seq != seq {
+		return WrapEvent{}, false
+	}
+
+	return WrapEvent{Code: r.code, Time: r.time}, true
+}
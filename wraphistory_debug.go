@@ -0,0 +1,7 @@
+// +build errorsdebug
+
+package errors
+
+func init() {
+	RecordWraps = true
+}
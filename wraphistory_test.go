@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func resetWrapHistory(size int) func() {
+	saved := WrapHistorySize
+	WrapHistorySize = size
+	wrapRing.Lock()
+	wrapRing.records, wrapRing.seq = nil, 0
+	wrapRing.Unlock()
+	RecordWraps = true
+
+	return func() {
+		RecordWraps = false
+		WrapHistorySize = saved
+	}
+}
+
+func wrapInMiddleware(err error) error {
+	return WrapCode(err, "test.history.middleware")
+}
+
+func TestWrapHistory(t *testing.T) {
+	defer resetWrapHistory(16)()
+
+	err := WrapCode(io.EOF, "test.history.store")
+	err = Wrap(err, "load")
+	err = wrapInMiddleware(err)
+
+	events := WrapHistory(err)
+	if len(events) != 2 {
+		t.Fatalf("WrapHistory: got %d events, want 2: %v", len(events), events)
+	}
+	if events[0].Code != "test.history.store" || events[1].Code != "test.history.middleware" {
+		t.Errorf("WrapHistory: got codes %s, %s", events[0].Code, events[1].Code)
+	}
+	if got := fmt.Sprintf("%n", events[1].Frame); got != "wrapInMiddleware" {
+		t.Errorf("middleware frame: got %s, want wrapInMiddleware", got)
+	}
+	if got := fmt.Sprintf("%+v", events[0].Frame); !strings.Contains(got, "TestWrapHistory") || !strings.Contains(got, "wraphistory_test.go:") {
+		t.Errorf("store frame: got %s", got)
+	}
+	if events[1].Time.Before(events[0].Time) {
+		t.Errorf("WrapHistory: events out of order: %v", events)
+	}
+
+	// Errors of the same code created at other call sites are not mixed up.
+	var errs []error
+	for i := 0; i < 2; i++ {
+		errs = append(errs, NewCode("test.history.loop"))
+	}
+	other := NewCode("test.history.loop")
+	if got := WrapHistory(other); len(got) != 1 || got[0].Frame == WrapHistory(errs[0])[0].Frame {
+		t.Errorf("WrapHistory: got %v for a different call site", got)
+	}
+
+	// Errors created at the same call site each get their own event.
+	first, second := WrapHistory(errs[0]), WrapHistory(errs[1])
+	if len(first) != 1 || len(second) != 1 || first[0].Time.After(second[0].Time) || errs[0].(*Error).seq == errs[1].(*Error).seq {
+		t.Errorf("WrapHistory: got %v and %v for errors of the same call site", first, second)
+	}
+
+	if got := WrapHistory(New("plain")); len(got) != 0 {
+		t.Errorf("WrapHistory(plain): got %v", got)
+	}
+
+	for _, err := range []error{
+		FromCoder(testCoder{code: "test.history.coder"}),
+		FromProblem([]byte(`{"type":"about:blank","title":"remote","code":"test.history.problem"}`)),
+	} {
+		if got := WrapHistory(err); len(got) != 1 || got[0].Code != Code(err) {
+			t.Errorf("WrapHistory(%v): got %v", err, got)
+		}
+	}
+}
+
+func TestWrapHistoryEviction(t *testing.T) {
+	defer resetWrapHistory(2)()
+
+	first := NewCode("test.history.first")
+	NewCode("test.history.second")
+	NewCode("test.history.third")
+
+	if got := WrapHistory(first); len(got) != 0 {
+		t.Errorf("WrapHistory: got %v, want the event evicted", got)
+	}
+
+	// Errors from the same call site as an evicted event are not given the
+	// event of a more recent error.
+	var errs []error
+	for i := 0; i < 3; i++ {
+		errs = append(errs, NewCode("test.history.loop"))
+	}
+	if got := WrapHistory(errs[0]); len(got) != 0 {
+		t.Errorf("WrapHistory: got %v, want the event evicted", got)
+	}
+	if got := WrapHistory(errs[2]); len(got) != 1 || got[0].Code != "test.history.loop" {
+		t.Errorf("WrapHistory: got %v, want the last event", got)
+	}
+
+	RecordWraps = false
+	if got := WrapHistory(NewCode("test.history.disabled")); len(got) != 0 {
+		t.Errorf("WrapHistory: got %v with RecordWraps disabled", got)
+	}
+}
@@ -0,0 +1,232 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// xmlWire is the XML form of Wire:
+//
+//     <error schema_version="1">
+//       <layer>
+//         <code>user.not_found</code>
+//         <message>user not found</message>
+//         <params>
+//           <param name="id" type="int">7</param>
+//         </params>
+//       </layer>
+//       <layer>
+//         <message>EOF</message>
+//       </layer>
+//     </error>
+type xmlWire struct {
+	SchemaVersion int            `xml:"schema_version,attr"`
+	Chain         []xmlWireLayer `xml:"layer"`
+}
+
+type xmlWireLayer struct {
+	Code     string         `xml:"code,omitempty"`
+	Message  string         `xml:"message"`
+	Params   *xmlWireParams `xml:"params"`
+	ID       string         `xml:"id,omitempty"`
+	Wrapped  bool           `xml:"wrapped,omitempty"`
+	Upstream *Upstream      `xml:"upstream"`
+}
+
+type xmlWireParams struct {
+	Params []xmlWireParam `xml:"param"`
+}
+
+// xmlWireParam is a param of a layer. Strings have no type; the types of
+// ParamTypes are kept, and other values are typed "bool", "float" or, for
+// values without a text form of their own, "json".
+type xmlWireParam struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML implements xml.Marshaler. The layers of the chain are encoded
+// as layer elements with code, message, params, id and upstream elements;
+// each param is a param element with name and type attributes. The element
+// is named by start, as given by the field or value being marshaled.
+func (w Wire) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	x := xmlWire{SchemaVersion: w.SchemaVersion, Chain: make([]xmlWireLayer, len(w.Chain))}
+	for i, l := range w.Chain {
+		x.Chain[i] = xmlWireLayer{
+			Code:     l.Code,
+			Message:  l.Message,
+			ID:       l.ID,
+			Wrapped:  l.Wrapped,
+			Upstream: l.Upstream,
+		}
+
+		if len(l.Params) == 0 {
+			continue
+		}
+		params := make([]xmlWireParam, 0, len(l.Params))
+		for k, v := range l.Params {
+			p, err := xmlParam(k, l.ParamTypes[k], v)
+			if err != nil {
+				return err
+			}
+			params = append(params, p)
+		}
+		sort.Slice(params, func(a, b int) bool {
+			return params[a].Name < params[b].Name
+		})
+		x.Chain[i].Params = &xmlWireParams{params}
+	}
+
+	return e.EncodeElement(x, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler for elements encoded by
+// MarshalXML. Params are restored with their type; untyped params are
+// strings.
+func (w *Wire) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var x xmlWire
+	if err := d.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+
+	*w = Wire{SchemaVersion: x.SchemaVersion, Chain: make([]WireLayer, len(x.Chain))}
+	for i, l := range x.Chain {
+		var params []xmlWireParam
+		if l.Params != nil {
+			params = l.Params.Params
+		}
+		if len(params) > maxWireParams {
+			return Errorf("errors: wire layer has %d params, more than %d", len(params), maxWireParams)
+		}
+
+		layer := WireLayer{
+			Code:     l.Code,
+			Message:  l.Message,
+			ID:       l.ID,
+			Wrapped:  l.Wrapped,
+			Upstream: l.Upstream,
+		}
+		if len(params) > 0 {
+			layer.Params = make(map[string]interface{}, len(params))
+		}
+		for _, p := range params {
+			layer.Params[p.Name] = xmlParamValue(p)
+		}
+		w.Chain[i] = layer
+	}
+
+	return nil
+}
+
+// xmlParam returns the param element of the param named name with the value
+// v, whose ParamTypes type is t.
+func xmlParam(name, t string, v interface{}) (xmlWireParam, error) {
+	p := xmlWireParam{Name: name, Type: t}
+	switch v := v.(type) {
+	case string:
+		p.Value = v
+	case time.Time:
+		p.Type, p.Value = "time", v.Format(time.RFC3339Nano)
+	case time.Duration:
+		p.Type, p.Value = "duration", strconv.FormatInt(int64(v), 10)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if p.Type == "" {
+			p.Type = "int64"
+		}
+		p.Value = fmt.Sprint(v)
+	case bool:
+		p.Type, p.Value = "bool", strconv.FormatBool(v)
+	case float32:
+		p.Type, p.Value = "float", strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		p.Type, p.Value = "float", strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		if p.Type == "" {
+			p.Type = "float"
+		}
+		p.Value = v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return p, Wrapf(err, "errors: encode param %s", name)
+		}
+		p.Type, p.Value = "json", string(data)
+	}
+
+	return p, nil
+}
+
+// xmlParamValue returns the value of a param element. Values that do not
+// parse as their type are kept as strings.
+func xmlParamValue(p xmlWireParam) interface{} {
+	switch p.Type {
+	case "":
+		return p.Value
+	case "bool":
+		if b, err := strconv.ParseBool(p.Value); err == nil {
+			return b
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(p.Value, 64); err == nil {
+			return f
+		}
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(p.Value), &v); err == nil {
+			return v
+		}
+	case "time":
+		if v, ok := typedParam(p.Type, p.Value); ok {
+			return v
+		}
+	default:
+		if v, ok := typedParam(p.Type, json.Number(p.Value)); ok {
+			return v
+		}
+	}
+
+	return p.Value
+}
+
+// ToXML encodes err's chain, as returned by ToWire, as an error element in
+// the XML form of the wire format, for integrations that require XML error
+// envelopes. Stack traces are not encoded.
+// If err is nil, ToXML returns an empty document.
+func ToXML(err error) ([]byte, error) {
+	w := ToWire(err)
+	if w == nil {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := xml.NewEncoder(&buf).EncodeElement(w, xml.StartElement{Name: xml.Name{Local: "error"}}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FromXML decodes an error chain encoded by ToXML, as FromJSON does for
+// ToJSON. An empty document decodes to a nil error.
+func FromXML(data []byte) (error, error) {
+	if len(data) > MaxWireBytes {
+		return nil, Errorf("errors: wire payload of %d bytes exceeds %d bytes", len(data), MaxWireBytes)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var w Wire
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&w); err != nil && err != io.EOF {
+		return nil, Wrap(err, "errors: invalid wire payload")
+	}
+
+	return FromWire(&w)
+}
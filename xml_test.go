@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXMLRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	params := map[string]interface{}{
+		"id":      7,
+		"big":     uint64(1 << 63),
+		"name":    "a<b",
+		"ok":      true,
+		"ratio":   0.25,
+		"at":      at,
+		"timeout": 3 * time.Second,
+		"tags":    []string{"x", "y"},
+	}
+	err := WrapCode(WrapCodeWithParams(io.EOF, "test.xml.inner", params, "inner"), "test.xml", "outer")
+
+	data, xerr := ToXML(err)
+	if xerr != nil {
+		t.Fatalf("ToXML: %v", xerr)
+	}
+	for _, want := range []string{
+		`<error schema_version="1"><layer><code>test.xml</code><message>outer</message></layer>`,
+		`<param name="id" type="int">7</param>`,
+		`<param name="name">a&lt;b</param>`,
+		`<param name="tags" type="json">[&#34;x&#34;,&#34;y&#34;]</param>`,
+		`<layer><message>EOF</message></layer></error>`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("ToXML: missing %s in %s", want, data)
+		}
+	}
+
+	decoded, derr := FromXML(data)
+	if derr != nil {
+		t.Fatalf("FromXML: %v", derr)
+	}
+	if got, want := decoded.Error(), err.Error(); got != want {
+		t.Errorf("FromXML: got %q, want %q", got, want)
+	}
+
+	want := map[string]interface{}{}
+	for k, v := range params {
+		want[k] = v
+	}
+	want["tags"] = []interface{}{"x", "y"}
+	if got := Params(decoded.(*Error).Cause()); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromXML params:\ngot  %#v\nwant %#v", got, want)
+	}
+}
+
+func TestWireXMLElement(t *testing.T) {
+	type envelope struct {
+		XMLName xml.Name `xml:"Envelope"`
+		Fault   *Wire    `xml:"Body>Fault"`
+	}
+
+	data, err := xml.Marshal(envelope{Fault: ToWire(NewCode("test.xml.soap", "fault"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `<Envelope><Body><Fault schema_version="1"><layer><code>test.xml.soap</code><message>fault</message></layer></Fault></Body></Envelope>`; got != want {
+		t.Errorf("xml.Marshal: got %s, want %s", got, want)
+	}
+
+	var env envelope
+	if err := xml.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	if decoded, _ := FromWire(env.Fault); !HasCode(decoded, "test.xml.soap") {
+		t.Errorf("xml.Unmarshal: got %v", decoded)
+	}
+}
+
+func TestFromXMLInvalid(t *testing.T) {
+	if err, derr := FromXML(nil); err != nil || derr != nil {
+		t.Errorf("FromXML(nil): got %v, %v", err, derr)
+	}
+	if data, _ := ToXML(nil); data != nil {
+		t.Errorf("ToXML(nil): got %s", data)
+	}
+
+	tests := []string{
+		"<error>",
+		`<error schema_version="1"></error>`,
+		"<error><layer><params>" + strings.Repeat(`<param name="p">v</param>`, maxWireParams+1) + "</params></layer></error>",
+	}
+	for _, data := range tests {
+		if _, derr := FromXML([]byte(data)); derr == nil {
+			t.Errorf("FromXML(%.40q): want an error", data)
+		}
+	}
+}